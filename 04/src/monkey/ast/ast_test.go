@@ -2,6 +2,7 @@ package ast
 
 import (
 	"monkey/token"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +27,124 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestMarshalJSON(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token: token.Token{Type: token.LET, Literal: "let", Pos: token.Position{Line: 1, Column: 1}},
+				Name: &Identifier{
+					Token: token.Token{Type: token.IDENT, Literal: "x", Pos: token.Position{Line: 1, Column: 5}},
+					Value: "x",
+				},
+				Value: &HashLiteral{
+					Token: token.Token{Type: token.LBRACE, Literal: "{", Pos: token.Position{Line: 1, Column: 9}},
+					Pairs: []HashLiteralPair{
+						{
+							Key: &StringLiteral{
+								Token: token.Token{Type: token.STRING, Literal: "a", Pos: token.Position{Line: 1, Column: 10}},
+								Value: "a",
+							},
+							Value: &IntegerLiteral{
+								Token: token.Token{Type: token.INT, Literal: "1", Pos: token.Position{Line: 1, Column: 15}},
+								Value: 1,
+							},
+						},
+					},
+				},
+			},
+			&ExpressionStatement{
+				Token: token.Token{Type: token.IF, Literal: "if", Pos: token.Position{Line: 2, Column: 1}},
+				Expression: &IfExpression{
+					Token: token.Token{Type: token.IF, Literal: "if", Pos: token.Position{Line: 2, Column: 1}},
+					Condition: &Boolean{
+						Token: token.Token{Type: token.TRUE, Literal: "true", Pos: token.Position{Line: 2, Column: 5}},
+						Value: true,
+					},
+					Consequence: &BlockStatement{
+						Token: token.Token{Type: token.LBRACE, Literal: "{", Pos: token.Position{Line: 2, Column: 11}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := `{"kind":"Program","statements":[` +
+		`{"kind":"LetStatement","name":{"kind":"Identifier","pos":{"line":1,"column":5},"value":"x"},"pos":{"line":1,"column":1},` +
+		`"value":{"kind":"HashLiteral","pairs":[{"key":{"kind":"StringLiteral","pos":{"line":1,"column":10},"value":"a"},` +
+		`"value":{"kind":"IntegerLiteral","pos":{"line":1,"column":15},"value":1}}],"pos":{"line":1,"column":9}}},` +
+		`{"expression":{"alternative":null,"condition":{"kind":"Boolean","pos":{"line":2,"column":5},"value":true},` +
+		`"consequence":{"kind":"BlockStatement","pos":{"line":2,"column":11},"statements":[]},` +
+		`"kind":"IfExpression","pos":{"line":2,"column":1}},"kind":"ExpressionStatement","pos":{"line":2,"column":1}}]}`
+
+	actual, err := MarshalJSON(program)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %s", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("MarshalJSON wrong.\nexpected=%s\ngot=     %s", expected, actual)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	ident := func(name string) *Identifier {
+		return &Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+	}
+
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Name: ident("f"),
+				Value: &FunctionLiteral{
+					Parameters: []*Identifier{ident("x")},
+					Body: &BlockStatement{
+						Statements: []Statement{
+							&ExpressionStatement{
+								Expression: &InfixExpression{Left: ident("x"), Operator: "+", Right: ident("y")},
+							},
+						},
+					},
+				},
+			},
+			&ExpressionStatement{
+				Expression: &IfExpression{
+					Condition:   ident("c"),
+					Consequence: &BlockStatement{},
+					Alternative: nil,
+				},
+			},
+			&ExpressionStatement{Expression: nil},
+		},
+	}
+
+	var names []string
+	Inspect(program, func(node Node) bool {
+		if _, ok := node.(*FunctionLiteral); ok {
+			names = append(names, "fn")
+		}
+		if id, ok := node.(*Identifier); ok {
+			names = append(names, id.Value)
+		}
+		return true
+	})
+
+	expected := "f fn x x y c"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("wrong visiting order. expected=%q, got=%q", expected, strings.Join(names, " "))
+	}
+
+	// f 가 false 를 반환하면 자식 노드는 방문하지 않는다
+	names = nil
+	Inspect(program, func(node Node) bool {
+		if id, ok := node.(*Identifier); ok {
+			names = append(names, id.Value)
+		}
+		_, isFn := node.(*FunctionLiteral)
+		return !isFn
+	})
+
+	expected = "f c"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("wrong visiting order. expected=%q, got=%q", expected, strings.Join(names, " "))
+	}
+}
@@ -0,0 +1,66 @@
+package ast
+
+import (
+	"encoding/json"
+	"monkey/token"
+	"reflect"
+	"strings"
+)
+
+// MarshalJSON 은 AST 를 JSON 으로 직렬화한다. 각 노드는 다음과 같은 객체가 된다.
+//
+//	{"kind": "LetStatement", "pos": {"line": 1, "column": 1}, "name": {...}, "value": {...}}
+//
+// kind 는 노드의 타입 이름이고, pos 는 노드의 토큰이 시작하는 위치이다. Program 처럼 토큰이 없는 노드는 pos 가 없다.
+// 나머지 키는 노드의 필드 이름의 첫 글자를 소문자로 바꾼 것이며, 비어 있는 자식 노드는 null 이 된다.
+func MarshalJSON(node Node) ([]byte, error) {
+	return json.Marshal(jsonValue(reflect.ValueOf(node)))
+}
+
+// MarshalJSONIndent 는 MarshalJSON 과 같지만 indent 로 들여쓴 JSON 을 반환한다.
+func MarshalJSONIndent(node Node, indent string) ([]byte, error) {
+	return json.MarshalIndent(jsonValue(reflect.ValueOf(node)), "", indent)
+}
+
+var tokenType = reflect.TypeOf(token.Token{})
+
+// jsonValue 는 AST 의 값을 encoding/json 으로 직렬화할 수 있는 값으로 바꾼다
+func jsonValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonValue(v.Elem())
+
+	case reflect.Struct:
+		object := map[string]interface{}{}
+		// HashLiteralPair 처럼 노드가 아닌 구조체는 kind 없이 필드만 출력한다
+		if reflect.PtrTo(v.Type()).Implements(reflect.TypeOf((*Node)(nil)).Elem()) {
+			object["kind"] = v.Type().Name()
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type == tokenType {
+				object["pos"] = v.Field(i).Interface().(token.Token).Pos
+				continue
+			}
+			object[lowerFirst(field.Name)] = jsonValue(v.Field(i))
+		}
+		return object
+
+	case reflect.Slice:
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			elements[i] = jsonValue(v.Index(i))
+		}
+		return elements
+
+	default:
+		return v.Interface()
+	}
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
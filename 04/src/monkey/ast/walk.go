@@ -0,0 +1,77 @@
+package ast
+
+import "reflect"
+
+// Inspect 는 node 와 그 자식 노드들을 소스 코드에 나온 순서대로 깊이 우선으로 방문하며 f 를 호출한다.
+// f 가 false 를 반환하면 그 노드의 자식 노드들은 방문하지 않는다.
+// 파싱에 실패한 프로그램처럼 비어 있는 자식 노드는 건너뛴다.
+func Inspect(node Node, f func(Node) bool) {
+	if isNil(node) || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *LetStatement:
+		Inspect(n.Name, f)
+		Inspect(n.Value, f)
+	case *ReturnStatement:
+		Inspect(n.ReturnValue, f)
+	case *ExpressionStatement:
+		Inspect(n.Expression, f)
+	case *BlockStatement:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *PrefixExpression:
+		Inspect(n.Right, f)
+	case *InfixExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+	case *IfExpression:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+		Inspect(n.Alternative, f)
+	case *FunctionLiteral:
+		for _, p := range n.Parameters {
+			Inspect(p, f)
+		}
+		Inspect(n.Body, f)
+	case *CallExpression:
+		Inspect(n.Function, f)
+		for _, a := range n.Arguments {
+			Inspect(a, f)
+		}
+	case *ArrayLiteral:
+		for _, el := range n.Elements {
+			Inspect(el, f)
+		}
+	case *IndexExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Index, f)
+	case *SliceExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Start, f)
+		Inspect(n.End, f)
+		Inspect(n.Step, f)
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			Inspect(pair.Key, f)
+			Inspect(pair.Value, f)
+		}
+	case *ImportExpression:
+		Inspect(n.Path, f)
+	}
+}
+
+// isNil 은 node 가 nil 이거나 nil 포인터를 담은 인터페이스인지 확인한다
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
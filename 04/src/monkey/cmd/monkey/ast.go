@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"os"
+)
+
+// astCommand 는 소스 파일을 파싱한 AST 를 출력한다. --json 이 주어지면 노드의 종류, 필드, 위치를 JSON 으로 출력하고,
+// 그렇지 않으면 AST 를 다시 파싱할 수 있는 소스 코드로 출력한다.
+func astCommand(args []string) int {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the AST as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey ast [--json] <file>\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	filename := flags.Arg(0)
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, msg)
+		}
+		return 1
+	}
+
+	if !*asJSON {
+		fmt.Println(program.String())
+		return 0
+	}
+
+	out, err := ast.MarshalJSONIndent(program, "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"monkey"
+	"monkey/cover"
+	"os"
+	"path/filepath"
+)
+
+// coverCommand 는 스크립트를 평가하면서 실행된 문과 분기를 세어 보고서를 쓴다.
+// 스크립트와 스크립트가 import 한 모듈들을 보고하며, 스크립트가 에러로 끝나도 그때까지의 결과를 쓴다.
+func coverCommand(args []string) int {
+	flags := flag.NewFlagSet("cover", flag.ContinueOnError)
+	output := flags.String("o", "", "write the report to `file` instead of stderr")
+	format := flags.String("format", "text", "report format: text, html or lcov")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey cover [-o file] [-format text|html|lcov] <file>\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	var write func(io.Writer, []*cover.File) error
+	switch *format {
+	case "text":
+		write = cover.WriteText
+	case "html":
+		write = cover.WriteHTML
+	case "lcov":
+		write = cover.WriteLCOV
+	default:
+		fmt.Fprintf(os.Stderr, "monkey cover: unknown format %q\n", *format)
+		return 2
+	}
+
+	filename := flags.Arg(0)
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	c := cover.New()
+	interp := monkey.New()
+	interp.Options.Filename = filename
+	interp.Options.Hook = c
+	status := 0
+	if _, err := interp.Eval(string(src)); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+		var parseErr *monkey.ParseError
+		if errors.As(err, &parseErr) {
+			return 1
+		}
+		status = 1
+	}
+
+	// 실행된 문이 없더라도 스크립트 자신은 항상 보고한다
+	main, err := filepath.Abs(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	names := []string{main}
+	for _, name := range c.Files() {
+		if name != main && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	var files []*cover.File
+	for _, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		f, err := c.Analyze(name, string(src))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			return 1
+		}
+		files = append(files, f)
+	}
+
+	out := io.Writer(os.Stderr)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := write(out, files); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return status
+}
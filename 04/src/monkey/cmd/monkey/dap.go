@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"monkey/dap"
+	"os"
+)
+
+// dapCommand 는 표준 입출력으로 편집기와 통신하는 디버그 어댑터를 실행한다.
+func dapCommand(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprint(os.Stderr, "usage: monkey dap\n")
+		return 2
+	}
+
+	if err := dap.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "monkey dap: %s\n", err)
+		return 1
+	}
+	return 0
+}
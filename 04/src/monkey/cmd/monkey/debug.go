@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"monkey"
+	"monkey/debug"
+	"os"
+)
+
+// debugCommand 는 스크립트를 디버거 콘솔에서 실행한다. 첫 번째 문에서 멈추므로 그 전에 중단점을 설정할 수 있다.
+func debugCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, "usage: monkey debug <file>\n")
+		return 2
+	}
+
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	d := debug.New(debug.NewConsole(os.Stdin, os.Stdout))
+	d.StopOnEntry()
+
+	interp := monkey.New()
+	interp.Options.Filename = args[0]
+	interp.Options.Hook = d
+	_, err = interp.Eval(string(src))
+	if errors.Is(err, debug.ErrQuit) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", args[0], err)
+		return 1
+	}
+	fmt.Println("program finished")
+	return 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"monkey/format"
+	"os"
+)
+
+// fmtCommand 는 소스 파일들을 표준 형식으로 출력한다. -w 가 주어지면 형식이 바뀐 파일을 덮어쓰고,
+// 파일이 주어지지 않으면 표준 입력을 형식에 맞추어 표준 출력으로 출력한다.
+func fmtCommand(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "write result to source files instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey fmt [-w] [files...]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprint(os.Stderr, "monkey fmt: cannot use -w with standard input\n")
+			return 2
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		out, err := format.Source(src)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "<stdin>: %s\n", err)
+			return 1
+		}
+		os.Stdout.Write(out)
+		return 0
+	}
+
+	status := 0
+	for _, filename := range flags.Args() {
+		if err := formatFile(filename, *write); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func formatFile(filename string, write bool) error {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	out, err := format.Source(src)
+	if err != nil {
+		return err
+	}
+
+	if !write {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if bytes.Equal(src, out) {
+		return nil
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, out, info.Mode().Perm())
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"monkey/lexer"
+	"monkey/lint"
+	"monkey/parser"
+	"monkey/testname"
+	"os"
+	"sort"
+	"strings"
+)
+
+// lintCommand 는 소스 파일들을 정적 분석하여 찾아낸 문제들을 file:line:column 형식으로 출력한다.
+// 문제가 하나라도 있으면 1 을 반환한다.
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	disable := flags.String("disable", "", "comma-separated rule IDs to disable")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey lint [-disable rules] <files...>\n")
+		flags.PrintDefaults()
+		fmt.Fprint(os.Stderr, "rules:\n")
+		for _, rule := range ruleIDs() {
+			fmt.Fprintf(os.Stderr, "  %-18s %s\n", rule, lint.Rules[rule])
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	var opts lint.Options
+	if *disable != "" {
+		for _, rule := range strings.Split(*disable, ",") {
+			rule = strings.TrimSpace(rule)
+			if _, ok := lint.Rules[rule]; !ok {
+				fmt.Fprintf(os.Stderr, "monkey lint: unknown rule %q\n", rule)
+				return 2
+			}
+			opts.Disabled = append(opts.Disabled, rule)
+		}
+	}
+
+	status := 0
+	for _, filename := range flags.Args() {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+
+		p := parser.New(lexer.New(string(src)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			for _, msg := range p.Errors() {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filename, msg)
+			}
+			status = 1
+			continue
+		}
+
+		opts.Test = strings.HasSuffix(filename, testname.FileSuffix)
+		for _, d := range lint.Lint(program, opts) {
+			fmt.Printf("%s:%s\n", filename, d)
+			status = 1
+		}
+	}
+	return status
+}
+
+func ruleIDs() []string {
+	rules := make([]string, 0, len(lint.Rules))
+	for rule := range lint.Rules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
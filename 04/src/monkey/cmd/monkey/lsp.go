@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"monkey/lsp"
+	"os"
+)
+
+// lspCommand 는 표준 입출력으로 클라이언트와 통신하는 언어 서버를 실행한다.
+func lspCommand(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprint(os.Stderr, "usage: monkey lsp\n")
+		return 2
+	}
+
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "monkey lsp: %s\n", err)
+		return 1
+	}
+	return 0
+}
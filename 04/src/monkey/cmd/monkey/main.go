@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"monkey/repl"
+	"os"
+	"os/user"
+)
+
+const usage = `usage:
+  monkey              start the REPL
+  monkey run <file>   run a Monkey script
+  monkey fmt [-w] [files...]
+                      format Monkey source files
+  monkey tokens <file>
+                      print the tokens of a file as JSON
+  monkey ast [--json] <file>
+                      print the syntax tree of a file
+  monkey lint [-disable rules] <files...>
+                      report common mistakes in Monkey source files
+  monkey lsp          start a language server on stdin/stdout
+  monkey debug <file> run a Monkey script in the interactive debugger
+  monkey dap          start a debug adapter on stdin/stdout
+  monkey profile [-o file] [-format report|collapsed|pprof] <file>
+                      run a Monkey script and report time spent per function
+  monkey cover [-o file] [-format text|html|lcov] <file>
+                      run a Monkey script and report statement and branch coverage
+  monkey test [-v] [-run regexp] [-timeout duration] [files or directories...]
+                      run the test_* functions in *_test.mk files
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		startRepl()
+		return
+	}
+
+	switch os.Args[1] {
+	case "run":
+		os.Exit(runCommand(os.Args[2:]))
+	case "fmt":
+		os.Exit(fmtCommand(os.Args[2:]))
+	case "tokens":
+		os.Exit(tokensCommand(os.Args[2:]))
+	case "ast":
+		os.Exit(astCommand(os.Args[2:]))
+	case "lint":
+		os.Exit(lintCommand(os.Args[2:]))
+	case "lsp":
+		os.Exit(lspCommand(os.Args[2:]))
+	case "debug":
+		os.Exit(debugCommand(os.Args[2:]))
+	case "dap":
+		os.Exit(dapCommand(os.Args[2:]))
+	case "profile":
+		os.Exit(profileCommand(os.Args[2:]))
+	case "cover":
+		os.Exit(coverCommand(os.Args[2:]))
+	case "test":
+		os.Exit(testCommand(os.Args[2:]))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+func startRepl() {
+	user, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Hello %s! This is the Monkey programming language!\n",
+		user.Username)
+	fmt.Printf("Feel free to type in commands\n")
+	repl.Start(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"monkey"
+	"monkey/profile"
+	"os"
+)
+
+// profileCommand 는 스크립트를 평가하면서 함수마다 호출 횟수와 시간을 측정해 쓴다.
+// 스크립트가 에러로 끝나도 그때까지의 결과를 쓴다.
+func profileCommand(args []string) int {
+	flags := flag.NewFlagSet("profile", flag.ContinueOnError)
+	output := flags.String("o", "", "write the profile to `file` instead of stderr")
+	format := flags.String("format", "report", "profile format: report, collapsed or pprof")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey profile [-o file] [-format report|collapsed|pprof] <file>\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	var write func(*profile.Profiler, io.Writer) error
+	switch *format {
+	case "report":
+		write = (*profile.Profiler).WriteReport
+	case "collapsed":
+		write = (*profile.Profiler).WriteCollapsed
+	case "pprof":
+		if *output == "" {
+			fmt.Fprint(os.Stderr, "monkey profile: -format pprof requires -o\n")
+			return 2
+		}
+		write = (*profile.Profiler).WritePprof
+	default:
+		fmt.Fprintf(os.Stderr, "monkey profile: unknown format %q\n", *format)
+		return 2
+	}
+
+	filename := flags.Arg(0)
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	p := profile.New()
+	interp := monkey.New()
+	interp.Options.Filename = filename
+	interp.Options.Hook = p
+	_, evalErr := interp.Eval(string(src))
+	p.Stop()
+
+	status := 0
+	if evalErr != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", filename, evalErr)
+		status = 1
+	}
+
+	out := io.Writer(os.Stderr)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := write(p, out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return status
+}
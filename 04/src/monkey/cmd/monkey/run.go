@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"monkey"
+	"os"
+)
+
+// runCommand 는 스크립트 파일을 평가한다. import 의 상대 경로는 스크립트 파일의 디렉터리를 기준으로 찾는다.
+func runCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, "usage: monkey run <file>\n")
+		return 2
+	}
+
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	interp := monkey.New()
+	interp.Options.Filename = args[0]
+	if _, err := interp.Eval(string(src)); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", args[0], err)
+		return 1
+	}
+	return 0
+}
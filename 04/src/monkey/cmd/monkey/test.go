@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"monkey/testrunner"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// testCommand 는 테스트 파일들의 테스트를 실행하고 실패한 테스트와 파일마다의 결과를 출력한다.
+// 경로가 주어지지 않으면 현재 디렉터리에서 테스트 파일을 찾는다. 실패한 테스트가 하나라도 있으면 1 을 반환한다.
+func testCommand(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := flags.Bool("v", false, "print every test, including passing ones")
+	run := flags.String("run", "", "run only tests whose names match `regexp`")
+	timeout := flags.Duration("timeout", 0, "fail a test that runs longer than `duration` (0 means no limit)")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage: monkey test [-v] [-run regexp] [-timeout duration] [files or directories...]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	r := &testrunner.Runner{}
+	r.Options.Timeout = *timeout
+	if *run != "" {
+		re, err := regexp.Compile(*run)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "monkey test: invalid -run: %s\n", err)
+			return 2
+		}
+		r.Run = re
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := testrunner.Find(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprint(os.Stderr, "monkey test: no test files\n")
+		return 1
+	}
+
+	status := 0
+	for _, filename := range files {
+		start := time.Now()
+		results, err := r.RunFile(context.Background(), filename)
+		if err != nil {
+			fmt.Printf("FAIL\t%s\t%s\n", filename, err)
+			status = 1
+			continue
+		}
+
+		failed := 0
+		for _, res := range results {
+			if res.Passed() {
+				if *verbose {
+					fmt.Printf("--- PASS: %s (%.2fs)\n", res.Name, res.Elapsed.Seconds())
+				}
+				continue
+			}
+
+			failed++
+			fmt.Printf("--- FAIL: %s (%.2fs)\n", res.Name, res.Elapsed.Seconds())
+			msg := res.Err.Message
+			if !res.AssertionFailed() {
+				msg = "error: " + msg
+			}
+			fmt.Printf("    %s:%s: %s\n", filename, res.Pos, strings.ReplaceAll(msg, "\n", "\n    "))
+		}
+
+		elapsed := time.Since(start).Seconds()
+		switch {
+		case len(results) == 0:
+			fmt.Printf("?   \t%s\t[no tests to run]\n", filename)
+		case failed > 0:
+			fmt.Printf("FAIL\t%s\t%d failed, %d passed\t%.3fs\n", filename, failed, len(results)-failed, elapsed)
+			status = 1
+		default:
+			fmt.Printf("ok  \t%s\t%d passed\t%.3fs\n", filename, len(results), elapsed)
+		}
+	}
+	return status
+}
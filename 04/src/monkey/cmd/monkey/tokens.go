@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"monkey/lexer"
+	"os"
+)
+
+// tokensCommand 는 소스 파일의 토큰들을 위치와 함께 JSON 배열로 출력한다.
+func tokensCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, "usage: monkey tokens <file>\n")
+		return 2
+	}
+
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out, err := json.MarshalIndent(lexer.Tokenize(string(src)), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
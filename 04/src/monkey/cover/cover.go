@@ -0,0 +1,205 @@
+// Package cover 는 Monkey 스크립트의 문(statement)과 분기(branch) 커버리지를 측정한다.
+//
+// Coverage 는 evaluator.Hook 으로 평가를 관찰하며 문의 위치마다 실행 횟수를, if 식마다
+// 결과 블록과 else 쪽이 실행된 횟수를 센다. else 가 없는 if 식도 조건이 거짓인 경우를 else 쪽으로 센다.
+// 측정이 끝나면 파일의 소스 코드와 함께 Analyze 로 분석하여 텍스트, HTML, LCOV 형식의 보고서를 만든다.
+package cover
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"monkey/token"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type counts struct {
+	statements map[token.Position]int
+	branches   map[token.Position]*[2]int // [결과 블록, else 쪽]
+}
+
+// Coverage 는 evaluator.Options.Hook 으로 설정하는 커버리지 수집기이다.
+// spawn 으로 만든 태스크도 같은 Coverage 로 측정하므로 여러 고루틴에서 동시에 사용할 수 있다.
+type Coverage struct {
+	mu    sync.Mutex
+	files map[string]*counts
+}
+
+// New 는 빈 커버리지 수집기를 만든다.
+func New() *Coverage {
+	return &Coverage{files: map[string]*counts{}}
+}
+
+func (c *Coverage) file(name string) *counts {
+	f, ok := c.files[name]
+	if !ok {
+		f = &counts{statements: map[token.Position]int{}, branches: map[token.Position]*[2]int{}}
+		c.files[name] = f
+	}
+	return f
+}
+
+// Statement 는 evaluator.Hook 을 구현한다.
+func (c *Coverage) Statement(file string, stmt ast.Statement, env *object.Environment) error {
+	c.mu.Lock()
+	c.file(file).statements[stmt.Pos()]++
+	c.mu.Unlock()
+	return nil
+}
+
+// Branch 는 evaluator.BranchHook 을 구현한다.
+func (c *Coverage) Branch(file string, node *ast.IfExpression, taken bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	branches := c.file(file).branches
+	hits, ok := branches[node.Pos()]
+	if !ok {
+		hits = &[2]int{}
+		branches[node.Pos()] = hits
+	}
+	if taken {
+		hits[0]++
+	} else {
+		hits[1]++
+	}
+}
+
+// Enter 는 evaluator.Hook 을 구현한다.
+func (c *Coverage) Enter(fn object.Object, args []object.Object) {}
+
+// Exit 는 evaluator.Hook 을 구현한다.
+func (c *Coverage) Exit(fn object.Object, result object.Object) {}
+
+var _ evaluator.BranchHook = (*Coverage)(nil)
+
+// Files 는 실행된 문이 있는 파일들의 경로를 정렬해서 반환한다. 파일 없이 평가한 프로그램은 빈 문자열이다.
+func (c *Coverage) Files() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.files))
+	for name := range c.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Statement 는 문 하나의 커버리지이다.
+type Statement struct {
+	Pos  token.Position
+	Hits int
+}
+
+// Branch 는 if 식 하나의 커버리지이다. Pos 는 if 키워드의 위치이다.
+type Branch struct {
+	Pos token.Position
+	// Evaluated 는 조건을 평가한 횟수로, Then 과 Else 의 합이다
+	Evaluated int
+	Then      int
+	// Else 는 조건이 거짓이었던 횟수. HasElse 가 false 라면 실행한 블록 없이 NULL 이 된 횟수이다
+	Else    int
+	HasElse bool
+}
+
+// File 은 파일 하나의 커버리지 분석 결과이다.
+type File struct {
+	Name       string
+	Lines      []string
+	Statements []Statement // 소스 코드에 나온 순서
+	Branches   []Branch    // 소스 코드에 나온 순서
+}
+
+// Analyze 는 name 의 소스 코드 src 에 있는 모든 문과 if 식에 측정한 횟수를 붙인다.
+// 측정한 뒤에 소스 코드가 바뀌었다면 위치가 맞지 않는 횟수는 버려진다.
+func (c *Coverage) Analyze(name string, src string) (*File, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, fmt.Errorf("parser errors: %s", strings.Join(p.Errors(), "; "))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hits, ok := c.files[name]
+	if !ok {
+		// 한 번도 실행되지 않은 파일이다
+		hits = &counts{}
+	}
+
+	f := &File{Name: name, Lines: strings.Split(src, "\n")}
+	addStatements := func(statements []ast.Statement) {
+		for _, stmt := range statements {
+			f.Statements = append(f.Statements, Statement{Pos: stmt.Pos(), Hits: hits.statements[stmt.Pos()]})
+		}
+	}
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Program:
+			addStatements(node.Statements)
+		case *ast.BlockStatement:
+			addStatements(node.Statements)
+		case *ast.IfExpression:
+			b := Branch{Pos: node.Pos(), HasElse: node.Alternative != nil}
+			if n, ok := hits.branches[node.Pos()]; ok {
+				b.Then, b.Else = n[0], n[1]
+				b.Evaluated = b.Then + b.Else
+			}
+			f.Branches = append(f.Branches, b)
+		}
+		return true
+	})
+
+	sort.SliceStable(f.Statements, func(i, j int) bool { return before(f.Statements[i].Pos, f.Statements[j].Pos) })
+	sort.SliceStable(f.Branches, func(i, j int) bool { return before(f.Branches[i].Pos, f.Branches[j].Pos) })
+	return f, nil
+}
+
+func before(a, b token.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+// StatementCoverage 는 실행된 문의 수와 전체 문의 수를 반환한다.
+func (f *File) StatementCoverage() (covered, total int) {
+	for _, s := range f.Statements {
+		if s.Hits > 0 {
+			covered++
+		}
+	}
+	return covered, len(f.Statements)
+}
+
+// BranchCoverage 는 실행된 분기의 수와 전체 분기의 수를 반환한다. if 식 하나는 두 개의 분기를 가진다.
+func (f *File) BranchCoverage() (covered, total int) {
+	for _, b := range f.Branches {
+		if b.Then > 0 {
+			covered++
+		}
+		if b.Else > 0 {
+			covered++
+		}
+	}
+	return covered, 2 * len(f.Branches)
+}
+
+// lineHits 는 줄마다 그 줄에서 시작하는 문들의 실행 횟수 중 가장 큰 값을 반환한다. 문이 없는 줄은 없다
+func (f *File) lineHits() (lines []int, hits map[int]int) {
+	hits = map[int]int{}
+	for _, s := range f.Statements {
+		h, ok := hits[s.Pos.Line]
+		if !ok {
+			lines = append(lines, s.Pos.Line)
+		}
+		if !ok || s.Hits > h {
+			hits[s.Pos.Line] = s.Hits
+		}
+	}
+	return lines, hits
+}
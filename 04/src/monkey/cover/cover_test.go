@@ -0,0 +1,179 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"strings"
+	"testing"
+)
+
+const script = `let sign = fn(x) {
+  if (x < 0) {
+    return -1;
+  }
+  if (x == 0) { 0 } else { 1 }
+};
+let unused = fn() { puts("never") };
+sign(5);
+sign(-2);`
+
+func coverEval(t *testing.T, input string) *File {
+	t.Helper()
+	c := New()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	evaluator.EvalContext(context.Background(), program, object.NewEnvironment(), evaluator.Options{Hook: c})
+
+	f, err := c.Analyze("", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestAnalyze(t *testing.T) {
+	f := coverEval(t, script)
+
+	expectedStatements := []struct {
+		pos  string
+		hits int
+	}{
+		{"1:1", 1},
+		{"2:3", 2},
+		{"3:5", 1},
+		{"5:3", 1},
+		{"5:17", 0},
+		{"5:28", 1},
+		{"7:1", 1},
+		{"7:21", 0},
+		{"8:1", 1},
+		{"9:1", 1},
+	}
+	if len(f.Statements) != len(expectedStatements) {
+		t.Fatalf("wrong number of statements. got=%+v", f.Statements)
+	}
+	for i, tt := range expectedStatements {
+		s := f.Statements[i]
+		if s.Pos.String() != tt.pos || s.Hits != tt.hits {
+			t.Errorf("statements[%d] wrong. expected=%s %d, got=%s %d", i, tt.pos, tt.hits, s.Pos, s.Hits)
+		}
+	}
+
+	expectedBranches := []Branch{
+		{Evaluated: 2, Then: 1, Else: 1, HasElse: false},
+		{Evaluated: 1, Then: 0, Else: 1, HasElse: true},
+	}
+	if len(f.Branches) != len(expectedBranches) {
+		t.Fatalf("wrong number of branches. got=%+v", f.Branches)
+	}
+	for i, tt := range expectedBranches {
+		b := f.Branches[i]
+		b.Pos = tt.Pos
+		if b != tt {
+			t.Errorf("branches[%d] wrong. expected=%+v, got=%+v", i, tt, f.Branches[i])
+		}
+	}
+
+	if covered, total := f.StatementCoverage(); covered != 8 || total != 10 {
+		t.Errorf("wrong statement coverage. got=%d/%d", covered, total)
+	}
+	if covered, total := f.BranchCoverage(); covered != 3 || total != 4 {
+		t.Errorf("wrong branch coverage. got=%d/%d", covered, total)
+	}
+}
+
+func TestNotExecuted(t *testing.T) {
+	c := New()
+	f, err := c.Analyze("other.mk", "let x = 1;\nif (x) { x }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if covered, total := f.StatementCoverage(); covered != 0 || total != 3 {
+		t.Errorf("wrong statement coverage. got=%d/%d", covered, total)
+	}
+	if len(c.Files()) != 0 {
+		t.Errorf("Analyze recorded a file. got=%v", c.Files())
+	}
+
+	if _, err := c.Analyze("broken.mk", "let = 1;"); err == nil {
+		t.Errorf("Analyze of invalid source returned no error")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	f := coverEval(t, script)
+
+	var out bytes.Buffer
+	if err := WriteText(&out, []*File{f}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<input>: statements 8/10 (80.0%), branches 3/4 (75.0%)
+  5:17: statement not executed
+  7:21: statement not executed
+  5:3: then branch never taken
+`
+	if out.String() != expected {
+		t.Errorf("wrong text report.\nexpected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestWriteLCOV(t *testing.T) {
+	f := coverEval(t, script)
+	f.Name = "/src/sign.mk"
+
+	var out bytes.Buffer
+	if err := WriteLCOV(&out, []*File{f}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `TN:
+SF:/src/sign.mk
+BRDA:2,0,0,1
+BRDA:2,0,1,1
+BRDA:5,1,0,0
+BRDA:5,1,1,1
+BRF:4
+BRH:3
+DA:1,1
+DA:2,2
+DA:3,1
+DA:5,1
+DA:7,1
+DA:8,1
+DA:9,1
+LF:7
+LH:7
+end_of_record
+`
+	if out.String() != expected {
+		t.Errorf("wrong LCOV report.\nexpected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	f := coverEval(t, script)
+
+	var out bytes.Buffer
+	if err := WriteHTML(&out, []*File{f}); err != nil {
+		t.Fatal(err)
+	}
+	html := out.String()
+
+	for _, want := range []string{
+		"<p>statements 8/10 (80.0%), branches 3/4 (75.0%)</p>",
+		`<tr class="covered"><td class="num">2</td><td class="hits">2</td>`,
+		`<tr class="uncovered" title="if at 5:3: then 0, else 1"><td class="num">5</td>`,
+		`<tr class="uncovered"><td class="num">7</td><td class="hits">1</td><td class="src">let unused = fn() { puts(&#34;never&#34;) };</td>`,
+		`<tr><td class="num">4</td><td class="hits"></td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report does not contain %q\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package cover
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strconv"
+)
+
+func percent(covered, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(covered)*100/float64(total))
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "<input>"
+	}
+	return filepath.Base(name)
+}
+
+// WriteText 는 파일마다 문과 분기의 커버리지를 요약하고, 실행되지 않은 문과 분기의 위치를 쓴다.
+func WriteText(w io.Writer, files []*File) error {
+	var stmts, stmtTotal, branches, branchTotal int
+	for _, f := range files {
+		sc, st := f.StatementCoverage()
+		bc, bt := f.BranchCoverage()
+		stmts, stmtTotal, branches, branchTotal = stmts+sc, stmtTotal+st, branches+bc, branchTotal+bt
+
+		if _, err := fmt.Fprintf(w, "%s: statements %d/%d (%s), branches %d/%d (%s)\n",
+			displayName(f.Name), sc, st, percent(sc, st), bc, bt, percent(bc, bt)); err != nil {
+			return err
+		}
+
+		for _, s := range f.Statements {
+			if s.Hits == 0 {
+				if _, err := fmt.Fprintf(w, "  %s: statement not executed\n", s.Pos); err != nil {
+					return err
+				}
+			}
+		}
+		for _, b := range f.Branches {
+			var msg string
+			switch {
+			case b.Evaluated == 0:
+				msg = "if never evaluated"
+			case b.Then == 0:
+				msg = "then branch never taken"
+			case b.Else == 0 && b.HasElse:
+				msg = "else branch never taken"
+			case b.Else == 0:
+				msg = "condition never false"
+			default:
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", b.Pos, msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(files) > 1 {
+		_, err := fmt.Fprintf(w, "total: statements %d/%d (%s), branches %d/%d (%s)\n",
+			stmts, stmtTotal, percent(stmts, stmtTotal), branches, branchTotal, percent(branches, branchTotal))
+		return err
+	}
+	return nil
+}
+
+// WriteLCOV 는 genhtml 이나 편집기의 커버리지 도구가 읽는 LCOV tracefile 형식으로 쓴다.
+// 줄의 실행 횟수는 그 줄에서 시작하는 문들의 실행 횟수 중 가장 큰 값이고,
+// if 식마다 결과 블록과 else 쪽의 두 분기를 BRDA 로 쓴다.
+func WriteLCOV(w io.Writer, files []*File) error {
+	for _, f := range files {
+		if _, err := fmt.Fprintf(w, "TN:\nSF:%s\n", f.Name); err != nil {
+			return err
+		}
+
+		for i, b := range f.Branches {
+			then, els := "-", "-"
+			if b.Evaluated > 0 {
+				then, els = strconv.Itoa(b.Then), strconv.Itoa(b.Else)
+			}
+			if _, err := fmt.Fprintf(w, "BRDA:%d,%d,0,%s\nBRDA:%d,%d,1,%s\n",
+				b.Pos.Line, i, then, b.Pos.Line, i, els); err != nil {
+				return err
+			}
+		}
+		bc, bt := f.BranchCoverage()
+		if _, err := fmt.Fprintf(w, "BRF:%d\nBRH:%d\n", bt, bc); err != nil {
+			return err
+		}
+
+		lines, hits := f.lineHits()
+		covered := 0
+		for _, line := range lines {
+			if hits[line] > 0 {
+				covered++
+			}
+			if _, err := fmt.Fprintf(w, "DA:%d,%d\n", line, hits[line]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "LF:%d\nLH:%d\nend_of_record\n", len(lines), covered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Class  string // covered, uncovered, partial 또는 문이 없는 줄이면 빈 문자열
+	Hits   string
+	Title  string
+}
+
+type htmlFile struct {
+	Name       string
+	Statements string
+	Branches   string
+	Lines      []htmlLine
+}
+
+// WriteHTML 은 소스 코드의 줄마다 실행 여부를 색으로 표시한 HTML 문서를 쓴다.
+// 실행되지 않은 문이 있는 줄은 빨간색, 한쪽 분기만 실행된 if 식이 있는 줄은 노란색, 나머지 실행된 줄은 초록색이다.
+func WriteHTML(w io.Writer, files []*File) error {
+	var data []htmlFile
+	for _, f := range files {
+		sc, st := f.StatementCoverage()
+		bc, bt := f.BranchCoverage()
+		hf := htmlFile{
+			Name:       displayName(f.Name),
+			Statements: fmt.Sprintf("%d/%d (%s)", sc, st, percent(sc, st)),
+			Branches:   fmt.Sprintf("%d/%d (%s)", bc, bt, percent(bc, bt)),
+		}
+
+		_, hits := f.lineHits()
+		uncovered := map[int]bool{}
+		for _, s := range f.Statements {
+			if s.Hits == 0 {
+				uncovered[s.Pos.Line] = true
+			}
+		}
+		partial := map[int]string{}
+		for _, b := range f.Branches {
+			if b.Evaluated > 0 && (b.Then == 0 || b.Else == 0) {
+				partial[b.Pos.Line] = fmt.Sprintf("if at %s: then %d, else %d", b.Pos, b.Then, b.Else)
+			}
+		}
+
+		for i, text := range f.Lines {
+			line := htmlLine{Number: i + 1, Text: text}
+			if h, ok := hits[line.Number]; ok {
+				line.Hits = strconv.Itoa(h)
+				line.Class = "covered"
+			}
+			if title, ok := partial[line.Number]; ok {
+				line.Class, line.Title = "partial", title
+			}
+			if uncovered[line.Number] {
+				line.Class = "uncovered"
+			}
+			hf.Lines = append(hf.Lines, line)
+		}
+		data = append(data, hf)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+var htmlTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Monkey coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; font-family: monospace; }
+td { padding: 0 8px; white-space: pre; }
+td.num, td.hits { text-align: right; color: #888; }
+tr.covered td.src { background: #dfd; }
+tr.uncovered td.src { background: #fdd; }
+tr.partial td.src { background: #ffc; }
+</style>
+</head>
+<body>
+{{range .}}<h2>{{.Name}}</h2>
+<p>statements {{.Statements}}, branches {{.Branches}}</p>
+<table>
+{{range .Lines}}<tr{{if .Class}} class="{{.Class}}"{{end}}{{if .Title}} title="{{.Title}}"{{end}}><td class="num">{{.Number}}</td><td class="hits">{{.Hits}}</td><td class="src">{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
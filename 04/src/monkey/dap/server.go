@@ -0,0 +1,451 @@
+// Package dap 는 Monkey 스크립트를 편집기에서 디버깅하기 위한 Debug Adapter Protocol 서버를 제공한다.
+//
+// 서버는 표준 입출력 같은 스트림 위에서 메시지를 주고받으며, debug.Debugger 로 평가를 문 단위로 멈춘다.
+// 멈춘 동안에는 호출 스택과 각 프레임의 object.Environment 사슬을 스코프로 보여준다.
+// 스크립트는 하나의 스레드로 보이며, spawn 으로 만든 태스크는 디버깅하지 않는다.
+package dap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"monkey/ast"
+	"monkey/debug"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// threadID 는 스크립트를 평가하는 유일한 스레드의 ID 이다
+const threadID = 1
+
+// Server 는 하나의 클라이언트와 연결된 디버그 어댑터이다.
+// 요청은 Serve 를 호출한 고루틴에서 처리하고, 스크립트는 별도의 고루틴에서 평가한다.
+type Server struct {
+	in *bufio.Reader
+
+	writeMu sync.Mutex // 평가하는 고루틴도 이벤트를 쓴다
+	out     io.Writer
+	seq     int
+
+	debugger *debug.Debugger
+
+	// launch 와 configurationDone 요청을 모두 받으면 평가를 시작한다
+	program    *launchedProgram
+	configured bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       chan struct{} // 평가가 끝나면 닫힌다
+
+	mu      sync.Mutex
+	stopped bool
+	stack   []debug.Frame // 멈춘 동안의 호출 스택
+	resume  chan debug.Action
+
+	// refs 는 variablesReference 를 *object.Environment 나 펼쳐볼 수 있는 객체로 매핑한다.
+	// 멈출 때마다 새로 만든다
+	refs map[int]interface{}
+
+	// afterReply 는 현재 요청의 응답을 쓴 뒤에 호출된다
+	afterReply func()
+}
+
+type launchedProgram struct {
+	path    string
+	program *ast.Program
+	noDebug bool
+}
+
+// NewServer 는 r 에서 요청을 읽고 w 에 응답과 이벤트를 쓰는 서버를 만든다.
+func NewServer(r io.Reader, w io.Writer) *Server {
+	s := &Server{
+		in:     bufio.NewReader(r),
+		out:    w,
+		resume: make(chan debug.Action),
+		refs:   map[int]interface{}{},
+	}
+	s.debugger = debug.New(s)
+	return s
+}
+
+// Serve 는 disconnect 요청을 받거나 입력이 끝날 때까지 요청을 처리한다.
+// disconnect 요청을 받았다면 nil 을, 그렇지 않다면 에러를 반환한다. 어느 경우든 평가가 끝난 뒤에 반환한다.
+func (s *Server) Serve() error {
+	for {
+		msg, err := readMessage(s.in)
+		if err != nil {
+			s.terminate()
+			if err == io.EOF {
+				return errors.New("dap: connection closed before disconnect")
+			}
+			return err
+		}
+		if msg.Type != "request" {
+			continue
+		}
+
+		if msg.Command == "disconnect" {
+			s.terminate()
+			return s.reply(msg, nil, nil)
+		}
+
+		body, err := s.handle(msg)
+		if err := s.reply(msg, body, err); err != nil {
+			return err
+		}
+		if s.afterReply != nil {
+			s.afterReply()
+			s.afterReply = nil
+		}
+	}
+}
+
+func (s *Server) send(msg *message) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.seq++
+	msg.Seq = s.seq
+	return writeMessage(s.out, msg)
+}
+
+func (s *Server) reply(req *message, body interface{}, handleErr error) error {
+	success := handleErr == nil
+	msg := &message{Type: "response", RequestSeq: req.Seq, Command: req.Command, Success: &success}
+	if handleErr != nil {
+		msg.Message = handleErr.Error()
+	} else if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		msg.Body = raw
+	}
+	return s.send(msg)
+}
+
+func (s *Server) event(event string, body interface{}) error {
+	msg := &message{Type: "event", Event: event}
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		msg.Body = raw
+	}
+	return s.send(msg)
+}
+
+// handle 은 요청 하나를 처리하고 응답의 body 를 반환한다
+func (s *Server) handle(msg *message) (interface{}, error) {
+	switch msg.Command {
+	case "initialize":
+		s.afterReply = func() { s.event("initialized", nil) }
+		return Capabilities{SupportsConfigurationDoneRequest: true}, nil
+
+	case "launch":
+		var args LaunchRequestArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.launch(args)
+
+	case "configurationDone":
+		s.configured = true
+		s.afterReply = s.start
+		return nil, nil
+
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.setBreakpoints(args), nil
+
+	case "threads":
+		return ThreadsResponseBody{Threads: []Thread{{ID: threadID, Name: "main"}}}, nil
+
+	case "stackTrace":
+		var args StackTraceArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.stackTrace(args)
+
+	case "scopes":
+		var args ScopesArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.scopes(args)
+
+	case "variables":
+		var args VariablesArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.variables(args)
+
+	case "continue":
+		return ContinueResponseBody{AllThreadsContinued: true}, s.resumeAfterReply(debug.Continue)
+	case "next":
+		return nil, s.resumeAfterReply(debug.StepOver)
+	case "stepIn":
+		return nil, s.resumeAfterReply(debug.StepInto)
+	case "stepOut":
+		return nil, s.resumeAfterReply(debug.StepOut)
+	}
+
+	return nil, fmt.Errorf("unsupported request %q", msg.Command)
+}
+
+func decode(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// launch 는 스크립트를 파싱해 둔다. 평가는 configurationDone 요청을 받은 뒤에 시작한다
+func (s *Server) launch(args LaunchRequestArguments) error {
+	if s.program != nil {
+		return errors.New("program already launched")
+	}
+	if args.Program == "" {
+		return errors.New("launch: program is required")
+	}
+
+	src, err := os.ReadFile(args.Program)
+	if err != nil {
+		return err
+	}
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return fmt.Errorf("parser errors: %s", strings.Join(p.Errors(), "; "))
+	}
+
+	if args.StopOnEntry {
+		s.debugger.StopOnEntry()
+	}
+	s.program = &launchedProgram{path: args.Program, program: program, noDebug: args.NoDebug}
+	s.afterReply = s.start
+	return nil
+}
+
+// start 는 launch 와 configurationDone 을 모두 받았다면 새로운 고루틴에서 평가를 시작한다
+func (s *Server) start() {
+	if s.program == nil || !s.configured || s.done != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.ctx, s.cancel = ctx, cancel
+	s.done = make(chan struct{})
+
+	env := object.NewEnvironment()
+	// puts 의 출력은 프로토콜의 스트림을 깨뜨리지 않도록 import 한 모듈의 출력까지 모두 output 이벤트로 보낸다
+	opts := evaluator.Options{Filename: s.program.path, Stdout: outputWriter{s}}
+	if !s.program.noDebug {
+		opts.Hook = s.debugger
+	}
+
+	go func() {
+		defer close(s.done)
+		defer cancel()
+
+		exitCode := 0
+		result := evaluator.EvalContext(ctx, s.program.program, env, opts)
+		if errObj, ok := result.(*object.Error); ok && ctx.Err() == nil && !errors.Is(errObj, debug.ErrQuit) {
+			s.event("output", OutputEventBody{Category: "stderr", Output: errObj.Message + "\n"})
+			exitCode = 1
+		}
+		s.event("exited", ExitedEventBody{ExitCode: exitCode})
+		s.event("terminated", nil)
+	}()
+}
+
+// outputWriter 는 쓰인 내용을 stdout 분류의 output 이벤트로 보낸다
+type outputWriter struct{ s *Server }
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.s.event("output", OutputEventBody{Category: "stdout", Output: string(p)})
+	return len(p), nil
+}
+
+// terminate 는 평가 중이라면 평가를 멈추고 끝날 때까지 기다린다
+func (s *Server) terminate() {
+	if s.done == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// Stopped 는 debug.Frontend 를 구현한다. stopped 이벤트를 보내고 평가를 이어가는 요청을 기다린다.
+// 그 전에 연결이 끊어지면 평가를 멈춘다.
+func (s *Server) Stopped(d *debug.Debugger, reason debug.Reason) debug.Action {
+	s.mu.Lock()
+	s.stopped = true
+	s.stack = d.Stack()
+	s.mu.Unlock()
+
+	s.event("stopped", StoppedEventBody{Reason: string(reason), ThreadID: threadID, AllThreadsStopped: true})
+	select {
+	case action := <-s.resume:
+		return action
+	case <-s.ctx.Done():
+		return debug.Quit
+	}
+}
+
+// resumeAfterReply 는 응답을 보낸 뒤에 멈춘 평가를 action 으로 이어가게 한다
+func (s *Server) resumeAfterReply(action debug.Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		return errors.New("program is not stopped")
+	}
+	s.stopped = false
+	s.stack = nil
+	s.refs = map[int]interface{}{}
+	s.afterReply = func() { s.resume <- action }
+	return nil
+}
+
+func (s *Server) setBreakpoints(args SetBreakpointsArguments) SetBreakpointsResponseBody {
+	path := args.Source.Path
+	s.debugger.ClearBreakpoints(path)
+
+	body := SetBreakpointsResponseBody{Breakpoints: []Breakpoint{}}
+	for _, bp := range args.Breakpoints {
+		s.debugger.SetBreakpoint(path, bp.Line)
+		body.Breakpoints = append(body.Breakpoints, Breakpoint{
+			Verified: true,
+			Line:     bp.Line,
+			Source:   &Source{Name: filepath.Base(path), Path: path},
+		})
+	}
+	return body
+}
+
+// frames 는 멈춘 동안의 호출 스택을 반환한다
+func (s *Server) frames() ([]debug.Frame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		return nil, errors.New("program is not stopped")
+	}
+	return s.stack, nil
+}
+
+func (s *Server) stackTrace(args StackTraceArguments) (interface{}, error) {
+	stack, err := s.frames()
+	if err != nil {
+		return nil, err
+	}
+
+	body := StackTraceResponseBody{StackFrames: []StackFrame{}, TotalFrames: len(stack)}
+	for i := args.StartFrame; i < len(stack); i++ {
+		if args.Levels > 0 && len(body.StackFrames) == args.Levels {
+			break
+		}
+		frame := stack[i]
+		sf := StackFrame{ID: i + 1, Name: frame.Function, Line: frame.Pos.Line, Column: frame.Pos.Column}
+		if frame.File != "" {
+			sf.Source = &Source{Name: filepath.Base(frame.File), Path: frame.File}
+		}
+		body.StackFrames = append(body.StackFrames, sf)
+	}
+	return body, nil
+}
+
+// scopes 는 프레임의 환경부터 가장 바깥의 환경까지를 차례로 스코프로 보여준다.
+// 프레임의 환경은 Locals, 가장 바깥의 환경은 Globals, 그 사이의 함수를 정의한 환경들은 Closure 이다.
+func (s *Server) scopes(args ScopesArguments) (interface{}, error) {
+	stack, err := s.frames()
+	if err != nil {
+		return nil, err
+	}
+	if args.FrameID < 1 || args.FrameID > len(stack) {
+		return nil, fmt.Errorf("invalid frame id %d", args.FrameID)
+	}
+
+	body := ScopesResponseBody{Scopes: []Scope{}}
+	for env := stack[args.FrameID-1].Env; env != nil; env = env.Outer() {
+		name := "Closure"
+		switch {
+		case env.Outer() == nil:
+			name = "Globals"
+		case len(body.Scopes) == 0:
+			name = "Locals"
+		}
+		body.Scopes = append(body.Scopes, Scope{Name: name, VariablesReference: s.reference(env)})
+	}
+	return body, nil
+}
+
+func (s *Server) reference(v interface{}) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref := len(s.refs) + 1
+	s.refs[ref] = v
+	return ref
+}
+
+// variables 는 환경의 바인딩이나 배열과 해시의 원소들을 반환한다. 내장함수는 보여주지 않는다
+func (s *Server) variables(args VariablesArguments) (interface{}, error) {
+	s.mu.Lock()
+	v, ok := s.refs[args.VariablesReference]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("invalid variables reference %d", args.VariablesReference)
+	}
+
+	body := VariablesResponseBody{Variables: []Variable{}}
+	switch v := v.(type) {
+	case *object.Environment:
+		for _, name := range v.Names() {
+			val, _ := v.Get(name)
+			if _, ok := val.(*object.Builtin); ok {
+				continue
+			}
+			body.Variables = append(body.Variables, s.variable(name, val))
+		}
+	case *object.Array:
+		for i, elem := range v.Elements {
+			body.Variables = append(body.Variables, s.variable("["+strconv.Itoa(i)+"]", elem))
+		}
+	case *object.Hash:
+		for _, pair := range v.Pairs() {
+			body.Variables = append(body.Variables, s.variable(pair.Key.Inspect(), pair.Value))
+		}
+	}
+	return body, nil
+}
+
+func (s *Server) variable(name string, val object.Object) Variable {
+	variable := Variable{Name: name, Value: val.Inspect(), Type: string(val.Type())}
+	switch val := val.(type) {
+	case *object.Array:
+		if len(val.Elements) != 0 {
+			variable.VariablesReference = s.reference(val)
+		}
+	case *object.Hash:
+		if val.Len() != 0 {
+			variable.VariablesReference = s.reference(val)
+		}
+	}
+	return variable
+}
@@ -0,0 +1,358 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// client 는 테스트를 위해 같은 프로세스에서 서버와 파이프로 연결된 클라이언트이다.
+// 서버는 요청과 상관없이 이벤트를 쓰므로 메시지는 별도의 고루틴에서 읽는다
+type client struct {
+	t      *testing.T
+	w      io.WriteCloser
+	msgs   chan *message
+	seq    int
+	events []*message // 응답을 기다리는 동안 받은 이벤트
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{t: t, w: clientOut, msgs: make(chan *message, 100), done: make(chan error, 1)}
+	go func() {
+		err := NewServer(serverIn, serverOut).Serve()
+		serverOut.Close()
+		c.done <- err
+	}()
+	go func() {
+		r := bufio.NewReader(clientIn)
+		for {
+			msg, err := readMessage(r)
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	return c
+}
+
+func (c *client) read() *message {
+	c.t.Helper()
+	msg, ok := <-c.msgs
+	if !ok {
+		c.t.Fatalf("connection closed")
+	}
+	return msg
+}
+
+// call 은 요청을 보내고 응답의 body 를 body 에 디코딩한다. 응답이 실패라면 그 메시지를 반환한다
+func (c *client) call(command string, args interface{}, body interface{}) string {
+	c.t.Helper()
+	c.seq++
+	raw, _ := json.Marshal(args)
+	if err := writeMessage(c.w, &message{Seq: c.seq, Type: "request", Command: command, Arguments: raw}); err != nil {
+		c.t.Fatalf("writing request: %s", err)
+	}
+
+	for {
+		msg := c.read()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		if msg.RequestSeq != c.seq || msg.Command != command {
+			c.t.Fatalf("unexpected response %s to request %d", msg.Command, msg.RequestSeq)
+		}
+		if msg.Success == nil || !*msg.Success {
+			return msg.Message
+		}
+		if body != nil {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				c.t.Fatalf("decoding body of %s: %s", command, err)
+			}
+		}
+		return ""
+	}
+}
+
+// waitEvent 는 event 이벤트를 받을 때까지 기다려 그 body 를 body 에 디코딩한다
+func (c *client) waitEvent(event string, body interface{}) {
+	c.t.Helper()
+	for {
+		var msg *message
+		if len(c.events) != 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.read()
+		}
+		if msg.Type != "event" || msg.Event != event {
+			continue
+		}
+		if body != nil {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				c.t.Fatalf("decoding body of %s event: %s", event, err)
+			}
+		}
+		return
+	}
+}
+
+func (c *client) expectStopped(reason string, line int) {
+	c.t.Helper()
+	var stopped StoppedEventBody
+	c.waitEvent("stopped", &stopped)
+	if stopped.Reason != reason || stopped.ThreadID != threadID {
+		c.t.Fatalf("wrong stopped event. got=%+v", stopped)
+	}
+
+	var trace StackTraceResponseBody
+	if msg := c.call("stackTrace", StackTraceArguments{ThreadID: threadID}, &trace); msg != "" {
+		c.t.Fatalf("stackTrace failed: %s", msg)
+	}
+	if top := trace.StackFrames[0]; top.Line != line {
+		c.t.Fatalf("stopped at wrong line. expected=%d, got=%d", line, top.Line)
+	}
+}
+
+func (c *client) disconnect() {
+	c.t.Helper()
+	if msg := c.call("disconnect", nil, nil); msg != "" {
+		c.t.Fatalf("disconnect failed: %s", msg)
+	}
+	c.w.Close()
+	if err := <-c.done; err != nil {
+		c.t.Errorf("Serve returned error: %s", err)
+	}
+}
+
+const script = `let add = fn(a, b) {
+  let sum = a + b;
+  sum
+};
+let nums = [1, 2];
+let total = add(nums[0], nums[1]);
+puts(total);`
+
+func writeScript(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.mk")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func (c *client) launch(args LaunchRequestArguments, breakpoints ...int) {
+	c.t.Helper()
+	var caps Capabilities
+	c.call("initialize", map[string]string{"adapterID": "monkey"}, &caps)
+	if !caps.SupportsConfigurationDoneRequest {
+		c.t.Errorf("configurationDone is not supported")
+	}
+	c.waitEvent("initialized", nil)
+
+	var bps []SourceBreakpoint
+	for _, line := range breakpoints {
+		bps = append(bps, SourceBreakpoint{Line: line})
+	}
+	var set SetBreakpointsResponseBody
+	c.call("setBreakpoints", SetBreakpointsArguments{Source: Source{Path: args.Program}, Breakpoints: bps}, &set)
+	if len(set.Breakpoints) != len(breakpoints) {
+		c.t.Errorf("wrong number of breakpoints. got=%d", len(set.Breakpoints))
+	}
+
+	if msg := c.call("launch", args, nil); msg != "" {
+		c.t.Fatalf("launch failed: %s", msg)
+	}
+	c.call("configurationDone", nil, nil)
+}
+
+func TestDebugSession(t *testing.T) {
+	path := writeScript(t)
+	c := newClient(t)
+	c.launch(LaunchRequestArguments{Program: path}, 2)
+
+	c.expectStopped("breakpoint", 2)
+
+	var threads ThreadsResponseBody
+	c.call("threads", nil, &threads)
+	if len(threads.Threads) != 1 || threads.Threads[0].ID != threadID {
+		t.Errorf("wrong threads. got=%+v", threads)
+	}
+
+	var trace StackTraceResponseBody
+	c.call("stackTrace", StackTraceArguments{ThreadID: threadID}, &trace)
+	if trace.TotalFrames != 2 {
+		t.Fatalf("wrong number of frames. got=%d", trace.TotalFrames)
+	}
+	frames := trace.StackFrames
+	if frames[0].Name != "add" || frames[0].Column != 3 ||
+		frames[1].Name != "<main>" || frames[1].Line != 6 ||
+		frames[0].Source == nil || frames[0].Source.Path != path {
+		t.Errorf("wrong stack frames. got=%+v", frames)
+	}
+
+	// add 의 환경과 전역 환경이 스코프가 된다
+	var scopes ScopesResponseBody
+	c.call("scopes", ScopesArguments{FrameID: frames[0].ID}, &scopes)
+	if len(scopes.Scopes) != 2 || scopes.Scopes[0].Name != "Locals" || scopes.Scopes[1].Name != "Globals" {
+		t.Fatalf("wrong scopes. got=%+v", scopes.Scopes)
+	}
+
+	var locals VariablesResponseBody
+	c.call("variables", VariablesArguments{VariablesReference: scopes.Scopes[0].VariablesReference}, &locals)
+	expectVariables(t, locals.Variables, map[string]string{"a": "1", "b": "2"})
+
+	var globals VariablesResponseBody
+	c.call("variables", VariablesArguments{VariablesReference: scopes.Scopes[1].VariablesReference}, &globals)
+	expectVariables(t, globals.Variables, map[string]string{
+		"add":  "fn(a, b) { let sum = (a + b); sum }",
+		"nums": "[1, 2]",
+	})
+
+	// 배열은 펼쳐볼 수 있다
+	var nums Variable
+	for _, v := range globals.Variables {
+		if v.Name == "nums" {
+			nums = v
+		}
+	}
+	var elements VariablesResponseBody
+	c.call("variables", VariablesArguments{VariablesReference: nums.VariablesReference}, &elements)
+	expectVariables(t, elements.Variables, map[string]string{"[0]": "1", "[1]": "2"})
+
+	c.call("next", map[string]int{"threadId": threadID}, nil)
+	c.expectStopped("step", 3)
+	c.call("stepOut", map[string]int{"threadId": threadID}, nil)
+	c.expectStopped("step", 7)
+
+	// 이전에 멈췄을 때의 참조는 더 이상 유효하지 않다
+	if msg := c.call("variables", VariablesArguments{VariablesReference: nums.VariablesReference}, nil); msg == "" {
+		t.Errorf("stale variables reference succeeded")
+	}
+
+	c.call("continue", map[string]int{"threadId": threadID}, nil)
+	var output OutputEventBody
+	c.waitEvent("output", &output)
+	if output.Category != "stdout" || output.Output != "3\n" {
+		t.Errorf("wrong output. got=%+v", output)
+	}
+	var exited ExitedEventBody
+	c.waitEvent("exited", &exited)
+	if exited.ExitCode != 0 {
+		t.Errorf("wrong exit code. got=%d", exited.ExitCode)
+	}
+	c.waitEvent("terminated", nil)
+
+	if msg := c.call("continue", map[string]int{"threadId": threadID}, nil); msg != "program is not stopped" {
+		t.Errorf("wrong error for continue after exit. got=%q", msg)
+	}
+	c.disconnect()
+}
+
+func TestStepIn(t *testing.T) {
+	path := writeScript(t)
+	c := newClient(t)
+	c.launch(LaunchRequestArguments{Program: path, StopOnEntry: true})
+
+	c.expectStopped("entry", 1)
+	for _, line := range []int{5, 6, 2} {
+		c.call("stepIn", map[string]int{"threadId": threadID}, nil)
+		c.expectStopped("step", line)
+	}
+
+	// 멈춘 상태에서 연결을 끊으면 평가를 멈춘다
+	c.disconnect()
+}
+
+func TestLaunchErrors(t *testing.T) {
+	c := newClient(t)
+	c.call("initialize", nil, nil)
+
+	if msg := c.call("launch", LaunchRequestArguments{}, nil); msg != "launch: program is required" {
+		t.Errorf("wrong error for missing program. got=%q", msg)
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.mk")
+	os.WriteFile(path, []byte("let = 1;"), 0o644)
+	if msg := c.call("launch", LaunchRequestArguments{Program: path}, nil); msg == "" {
+		t.Errorf("launching a program with parser errors succeeded")
+	}
+
+	if msg := c.call("evaluate", nil, nil); msg != `unsupported request "evaluate"` {
+		t.Errorf("wrong error for unsupported request. got=%q", msg)
+	}
+	c.disconnect()
+}
+
+func TestRuntimeError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "error.mk")
+	os.WriteFile(path, []byte("let x = 1;\nx + true;"), 0o644)
+
+	c := newClient(t)
+	c.launch(LaunchRequestArguments{Program: path})
+
+	var output OutputEventBody
+	c.waitEvent("output", &output)
+	if output.Category != "stderr" || output.Output != "type mismatch: INTEGER + BOOLEAN\n" {
+		t.Errorf("wrong output. got=%+v", output)
+	}
+	var exited ExitedEventBody
+	c.waitEvent("exited", &exited)
+	if exited.ExitCode != 1 {
+		t.Errorf("wrong exit code. got=%d", exited.ExitCode)
+	}
+	c.disconnect()
+}
+
+func TestModuleOutput(t *testing.T) {
+	// import 한 모듈의 puts 도 프로토콜의 스트림 대신 output 이벤트로 보낸다
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "lib.mk"), []byte(`puts("from module");`), 0o644)
+	path := filepath.Join(dir, "main.mk")
+	os.WriteFile(path, []byte(`import "lib.mk"; puts("from main");`), 0o644)
+
+	c := newClient(t)
+	c.launch(LaunchRequestArguments{Program: path, NoDebug: true})
+
+	for _, expected := range []string{"from module\n", "from main\n"} {
+		var output OutputEventBody
+		c.waitEvent("output", &output)
+		if output.Category != "stdout" || output.Output != expected {
+			t.Errorf("wrong output. expected=%q, got=%+v", expected, output)
+		}
+	}
+	c.waitEvent("terminated", nil)
+	c.disconnect()
+}
+
+func TestContentLengthLimit(t *testing.T) {
+	// 최대 크기를 넘는 Content-Length 는 본문을 할당하기 전에 거부한다
+	r := bufio.NewReader(strings.NewReader("Content-Length: 1099511627776\r\n\r\n{}"))
+	_, err := readMessage(r)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Errorf("expected Content-Length error. got=%v", err)
+	}
+}
+
+func expectVariables(t *testing.T, variables []Variable, expected map[string]string) {
+	t.Helper()
+	if len(variables) != len(expected) {
+		t.Errorf("wrong number of variables. expected=%d, got=%+v", len(expected), variables)
+		return
+	}
+	for _, v := range variables {
+		if expected[v.Name] != v.Value {
+			t.Errorf("wrong value of %s. expected=%q, got=%q", v.Name, expected[v.Name], v.Value)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// message 는 요청, 응답, 이벤트를 모두 표현하는 Debug Adapter Protocol 메시지이다. Type 으로 구분한다.
+type message struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"` // "request", "response", "event"
+
+	// 요청
+	Command   string          `json:"command,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+
+	// 응답. Command 는 요청의 Command 와 같다
+	RequestSeq int    `json:"request_seq,omitempty"`
+	Success    *bool  `json:"success,omitempty"` // 응답에만 있다
+	Message    string `json:"message,omitempty"`
+
+	// 이벤트
+	Event string `json:"event,omitempty"`
+
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// maxContentLength 는 readMessage 가 받아들이는 메시지 본문의 최대 크기이다.
+// 클라이언트가 보낸 Content-Length 만큼 미리 메모리를 할당하므로 터무니없이 큰 값은 거부한다
+const maxContentLength = 64 << 20
+
+// readMessage 는 Content-Length 헤더로 구분된 메시지 하나를 읽는다
+func readMessage(r *bufio.Reader) (*message, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	if length > maxContentLength {
+		return nil, fmt.Errorf("Content-Length %d exceeds the maximum of %d bytes", length, maxContentLength)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("decoding message: %w", err)
+	}
+	return &msg, nil
+}
+
+// writeMessage 는 msg 를 Content-Length 헤더와 함께 쓴다
+func writeMessage(w io.Writer, msg *message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,243 @@
+package debug
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/parser"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const consolePrompt = "(debug) "
+
+const consoleHelp = `commands:
+  break [file:]<line>  set a breakpoint (alias b)
+  clear [file:]<line>  delete a breakpoint
+  breakpoints          list breakpoints
+  continue             run until the next breakpoint (alias c)
+  step                 step into the next statement (alias s)
+  next                 step over function calls (alias n)
+  out                  run until the current function returns (alias o)
+  stack                print the call stack (alias bt)
+  frame <n>            select frame n for locals and print
+  locals               print the variables of the selected frame
+  print <expr>         evaluate an expression in the selected frame (alias p)
+  list                 show the source around the current line
+  quit                 stop the program (alias q)
+`
+
+// maxValueLength 보다 긴 값은 줄여서 보여준다
+const maxValueLength = 70
+
+// Console 은 한 줄씩 명령을 읽는 REPL 형태의 Frontend 이다.
+type Console struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	sources map[string][]string // 파일의 절대 경로를 줄들로 매핑한다
+	frame   int                 // locals 와 print 가 사용할 프레임. 멈출 때마다 0 으로 돌아간다
+}
+
+// NewConsole 은 in 에서 명령을 읽고 out 에 결과를 쓰는 콘솔을 만든다.
+func NewConsole(in io.Reader, out io.Writer) *Console {
+	return &Console{
+		in:      bufio.NewScanner(in),
+		out:     out,
+		sources: map[string][]string{},
+	}
+}
+
+// Stopped 는 Frontend 를 구현한다. 평가를 이어가는 명령을 읽을 때까지 명령을 처리한다.
+// 입력이 끝나면 Quit 을 반환한다.
+func (c *Console) Stopped(d *Debugger, reason Reason) Action {
+	c.frame = 0
+	top := d.Stack()[0]
+	fmt.Fprintf(c.out, "stopped at %s (%s) in %s\n", location(top), reason, top.Function)
+	c.printLine(top.File, top.Pos.Line, true)
+
+	for {
+		fmt.Fprint(c.out, consolePrompt)
+		if !c.in.Scan() {
+			fmt.Fprintln(c.out)
+			return Quit
+		}
+
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(c.in.Text()), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch cmd {
+		case "":
+		case "continue", "c":
+			return Continue
+		case "step", "s":
+			return StepInto
+		case "next", "n":
+			return StepOver
+		case "out", "o":
+			return StepOut
+		case "quit", "q":
+			return Quit
+
+		case "break", "b":
+			if file, line, ok := c.parseLocation(d, arg); ok {
+				bp := d.SetBreakpoint(file, line)
+				fmt.Fprintf(c.out, "breakpoint set at %s\n", bpLocation(bp))
+			}
+		case "clear":
+			if file, line, ok := c.parseLocation(d, arg); ok {
+				if !d.ClearBreakpoint(file, line) {
+					fmt.Fprintf(c.out, "no breakpoint at line %d\n", line)
+				}
+			}
+		case "breakpoints":
+			bps := d.Breakpoints()
+			if len(bps) == 0 {
+				fmt.Fprintln(c.out, "no breakpoints")
+			}
+			for _, bp := range bps {
+				fmt.Fprintln(c.out, bpLocation(bp))
+			}
+
+		case "stack", "bt":
+			for i, frame := range d.Stack() {
+				marker := " "
+				if i == c.frame {
+					marker = "*"
+				}
+				fmt.Fprintf(c.out, "%s#%d %s at %s\n", marker, i, frame.Function, location(frame))
+			}
+		case "frame":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 || n >= len(d.Stack()) {
+				fmt.Fprintf(c.out, "invalid frame %q\n", arg)
+				continue
+			}
+			c.frame = n
+			frame := d.Stack()[n]
+			fmt.Fprintf(c.out, "#%d %s at %s\n", n, frame.Function, location(frame))
+		case "locals":
+			c.printLocals(d.Stack()[c.frame])
+		case "print", "p":
+			c.print(d.Stack()[c.frame], arg)
+		case "list":
+			frame := d.Stack()[c.frame]
+			for line := frame.Pos.Line - 3; line <= frame.Pos.Line+3; line++ {
+				c.printLine(frame.File, line, line == frame.Pos.Line)
+			}
+
+		case "help", "h":
+			fmt.Fprint(c.out, consoleHelp)
+		default:
+			fmt.Fprintf(c.out, "unknown command %q; type help for a list of commands\n", cmd)
+		}
+	}
+}
+
+// parseLocation 은 "line" 이나 "file:line" 형태의 위치를 해석한다. 파일을 생략하면 현재 프레임의 파일을 사용한다
+func (c *Console) parseLocation(d *Debugger, arg string) (string, int, bool) {
+	file := d.Stack()[0].File
+	if i := strings.LastIndex(arg, ":"); i >= 0 {
+		file, arg = arg[:i], arg[i+1:]
+		// 상대 경로는 현재 파일의 디렉터리를 기준으로 한다
+		if cur := d.Stack()[0].File; cur != "" && !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(cur), file)
+		}
+	}
+	line, err := strconv.Atoi(arg)
+	if err != nil || line < 1 {
+		fmt.Fprintf(c.out, "invalid line %q\n", arg)
+		return "", 0, false
+	}
+	return file, line, true
+}
+
+func (c *Console) printLocals(frame Frame) {
+	if frame.Env == nil {
+		return
+	}
+	names := frame.Env.Names()
+	if len(names) == 0 {
+		fmt.Fprintln(c.out, "no variables")
+	}
+	for _, name := range names {
+		val, _ := frame.Env.Get(name)
+		fmt.Fprintf(c.out, "%s = %s\n", name, shorten(val.Inspect()))
+	}
+}
+
+// printTimeout 은 print 로 식을 평가하는 데 허용하는 시간. 끝나지 않는 식이 디버거를 멈추게 하지 않도록 한다
+const printTimeout = 5 * time.Second
+
+// print 는 식을 프레임의 환경에서 평가한다. import 는 프레임의 파일을 기준으로 찾는다. 평가는 디버깅하지 않는다
+func (c *Console) print(frame Frame, input string) {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintf(c.out, "parser errors: %s\n", strings.Join(p.Errors(), "; "))
+		return
+	}
+	if frame.Env == nil {
+		return
+	}
+	opts := evaluator.Options{Filename: frame.File, Timeout: printTimeout}
+	if result := evaluator.EvalContext(context.Background(), program, frame.Env, opts); result != nil {
+		fmt.Fprintln(c.out, result.Inspect())
+	}
+}
+
+// printLine 은 소스 코드의 한 줄을 줄 번호와 함께 출력한다. current 라면 앞에 > 를 붙인다
+func (c *Console) printLine(file string, line int, current bool) {
+	lines := c.source(file)
+	if line < 1 || line > len(lines) {
+		return
+	}
+	marker := " "
+	if current {
+		marker = ">"
+	}
+	fmt.Fprintf(c.out, "%s %4d  %s\n", marker, line, lines[line-1])
+}
+
+func (c *Console) source(file string) []string {
+	if file == "" {
+		return nil
+	}
+	lines, ok := c.sources[file]
+	if !ok {
+		// 읽을 수 없는 파일은 소스 없이 디버깅한다
+		if src, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(src), "\n")
+		}
+		c.sources[file] = lines
+	}
+	return lines
+}
+
+func location(frame Frame) string {
+	if frame.File == "" {
+		return frame.Pos.String()
+	}
+	return filepath.Base(frame.File) + ":" + frame.Pos.String()
+}
+
+func bpLocation(bp Breakpoint) string {
+	if bp.File == "" {
+		return "line " + strconv.Itoa(bp.Line)
+	}
+	return filepath.Base(bp.File) + ":" + strconv.Itoa(bp.Line)
+}
+
+func shorten(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) > maxValueLength {
+		return s[:maxValueLength-3] + "..."
+	}
+	return s
+}
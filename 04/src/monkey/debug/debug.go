@@ -0,0 +1,226 @@
+// Package debug 는 evaluator.Hook 으로 평가를 관찰하는 단계별 디버거를 제공한다.
+//
+// Debugger 는 줄 단위의 중단점과 step into/over/out 으로 평가를 멈추고, 멈출 때마다 Frontend 에게
+// 다음 동작을 묻는다. Frontend 는 멈춘 동안 호출 스택과 각 프레임의 변수를 살펴볼 수 있다.
+package debug
+
+import (
+	"errors"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/object"
+	"monkey/token"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrQuit 은 Frontend 가 Quit 을 반환해 평가를 멈췄을 때 평가 결과인 에러 객체의 Cause 이다.
+var ErrQuit = errors.New("debugging session terminated")
+
+// Action 은 멈춘 평가를 어떻게 이어갈지 정한다.
+type Action int
+
+const (
+	Continue Action = iota // 다음 중단점까지 실행한다
+	StepInto               // 다음 문에서 멈춘다
+	StepOver               // 호출한 함수 안에 들어가지 않고 다음 문에서 멈춘다
+	StepOut                // 현재 함수가 반환된 뒤의 문에서 멈춘다
+	Quit                   // 평가를 멈춘다
+)
+
+// Reason 은 평가가 멈춘 이유이다.
+type Reason string
+
+const (
+	ReasonEntry      Reason = "entry"
+	ReasonStep       Reason = "step"
+	ReasonBreakpoint Reason = "breakpoint"
+)
+
+// Frontend 는 멈춘 평가를 사용자에게 보여주고 다음 동작을 정한다.
+type Frontend interface {
+	// Stopped 는 평가가 멈출 때마다 평가하는 고루틴에서 호출된다. 반환할 때까지 평가는 멈춰 있다.
+	Stopped(d *Debugger, reason Reason) Action
+}
+
+// Frame 은 호출 스택의 프레임 하나이다.
+type Frame struct {
+	// Function 은 함수의 이름. 가장 바깥의 프레임은 "<main>", 이름이 없는 함수는 "<anonymous>" 이다
+	Function string
+	// File 과 Pos 는 프레임에서 마지막으로 실행을 시작한 문의 위치이다
+	File string
+	Pos  token.Position
+	// Env 는 그 문을 평가하는 환경이다
+	Env *object.Environment
+}
+
+// Breakpoint 는 파일의 한 줄에 설정한 중단점이다. File 은 절대 경로이며, 파일 없이 평가하는 프로그램은 빈 문자열이다.
+type Breakpoint struct {
+	File string
+	Line int
+}
+
+// Debugger 는 evaluator.Options.Hook 으로 설정하는 디버거이다.
+// 사용자 정의 함수의 호출만 프레임으로 만들며, 모듈의 최상위 문은 import 한 프레임에서 실행되는 것으로 취급한다.
+// spawn 으로 만든 태스크는 디버깅하지 않는다.
+type Debugger struct {
+	frontend Frontend
+
+	mu          sync.Mutex // 중단점은 평가하지 않는 고루틴에서도 바꿀 수 있다
+	breakpoints map[Breakpoint]bool
+
+	frames []*Frame
+	action Action
+	reason Reason // action 이 StepInto 일 때 멈추는 이유
+	depth  int    // 마지막으로 멈췄을 때의 호출 스택의 깊이
+}
+
+// New 는 멈출 때마다 frontend 를 호출하는 디버거를 만든다.
+func New(frontend Frontend) *Debugger {
+	return &Debugger{
+		frontend:    frontend,
+		breakpoints: map[Breakpoint]bool{},
+		frames:      []*Frame{{Function: "<main>"}},
+		action:      Continue,
+	}
+}
+
+// StopOnEntry 는 첫 번째 문을 실행하기 전에 멈추게 한다. 평가를 시작하기 전에 호출해야 한다.
+func (d *Debugger) StopOnEntry() {
+	d.action = StepInto
+	d.reason = ReasonEntry
+}
+
+// SetBreakpoint 는 file 의 line 번째 줄에 중단점을 설정한다. file 이 상대 경로라면 절대 경로로 바꾼다.
+func (d *Debugger) SetBreakpoint(file string, line int) Breakpoint {
+	bp := Breakpoint{File: absPath(file), Line: line}
+	d.mu.Lock()
+	d.breakpoints[bp] = true
+	d.mu.Unlock()
+	return bp
+}
+
+// ClearBreakpoint 는 중단점을 지운다. 중단점이 없었다면 false 를 반환한다.
+func (d *Debugger) ClearBreakpoint(file string, line int) bool {
+	bp := Breakpoint{File: absPath(file), Line: line}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.breakpoints[bp] {
+		return false
+	}
+	delete(d.breakpoints, bp)
+	return true
+}
+
+// ClearBreakpoints 는 file 의 모든 중단점을 지운다.
+func (d *Debugger) ClearBreakpoints(file string) {
+	file = absPath(file)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for bp := range d.breakpoints {
+		if bp.File == file {
+			delete(d.breakpoints, bp)
+		}
+	}
+}
+
+// Breakpoints 는 설정된 중단점들을 파일과 줄 순서로 반환한다.
+func (d *Debugger) Breakpoints() []Breakpoint {
+	d.mu.Lock()
+	result := make([]Breakpoint, 0, len(d.breakpoints))
+	for bp := range d.breakpoints {
+		result = append(result, bp)
+	}
+	d.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+func (d *Debugger) hasBreakpoint(file string, line int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.breakpoints[Breakpoint{File: file, Line: line}]
+}
+
+func absPath(file string) string {
+	if file == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return file
+}
+
+// Stack 은 호출 스택을 가장 안쪽의 프레임부터 반환한다. Frontend.Stopped 안에서만 호출해야 한다.
+func (d *Debugger) Stack() []Frame {
+	stack := make([]Frame, len(d.frames))
+	for i, frame := range d.frames {
+		stack[len(d.frames)-1-i] = *frame
+	}
+	return stack
+}
+
+// Statement 는 evaluator.Hook 을 구현한다. 멈춰야 하는 문이라면 Frontend 에게 다음 동작을 묻는다.
+func (d *Debugger) Statement(file string, stmt ast.Statement, env *object.Environment) error {
+	frame := d.frames[len(d.frames)-1]
+	pos := stmt.Pos()
+	// 한 줄에 있는 여러 문에서 중단점에 거듭 멈추지 않는다
+	sameLine := frame.File == file && frame.Pos.Line == pos.Line
+	frame.File, frame.Pos, frame.Env = file, pos, env
+
+	depth := len(d.frames)
+	var reason Reason
+	switch {
+	case !sameLine && d.hasBreakpoint(file, pos.Line):
+		reason = ReasonBreakpoint
+	case d.action == StepInto:
+		reason = d.reason
+	case d.action == StepOver && depth <= d.depth,
+		d.action == StepOut && depth < d.depth:
+		reason = ReasonStep
+	default:
+		return nil
+	}
+
+	action := d.frontend.Stopped(d, reason)
+	if action == Quit {
+		return ErrQuit
+	}
+	d.action = action
+	d.reason = ReasonStep
+	d.depth = depth
+	return nil
+}
+
+// Enter 는 evaluator.Hook 을 구현한다. 사용자 정의 함수의 호출이라면 프레임을 추가한다.
+func (d *Debugger) Enter(fn object.Object, args []object.Object) {
+	f, ok := fn.(*object.Function)
+	if !ok {
+		return
+	}
+	name := f.Name
+	if name == "" {
+		name = "<anonymous>"
+	}
+	d.frames = append(d.frames, &Frame{Function: name})
+}
+
+// Exit 는 evaluator.Hook 을 구현한다. Enter 에서 추가한 프레임을 제거한다.
+func (d *Debugger) Exit(fn object.Object, result object.Object) {
+	if _, ok := fn.(*object.Function); ok {
+		d.frames = d.frames[:len(d.frames)-1]
+	}
+}
+
+// Fork 는 evaluator.ForkHook 을 구현한다. spawn 으로 만든 태스크는 디버깅하지 않는다.
+func (d *Debugger) Fork() evaluator.Hook {
+	return nil
+}
@@ -0,0 +1,266 @@
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const program = `let add = fn(a, b) {
+  let sum = a + b;
+  sum
+};
+let x = 1;
+let y = add(x, 2);
+let z = add(y, 3);
+puts(z);`
+
+// scripted 는 멈출 때마다 위치를 기록하고 정해진 동작을 차례로 반환하는 Frontend 이다
+type scripted struct {
+	actions []Action
+	stops   []string
+}
+
+func (s *scripted) Stopped(d *Debugger, reason Reason) Action {
+	var names []string
+	for _, frame := range d.Stack() {
+		names = append(names, frame.Function)
+	}
+	s.stops = append(s.stops, fmt.Sprintf("%d %s %s", d.Stack()[0].Pos.Line, reason, strings.Join(names, "<")))
+
+	if len(s.actions) == 0 {
+		return Continue
+	}
+	action := s.actions[0]
+	s.actions = s.actions[1:]
+	return action
+}
+
+func debugEval(t *testing.T, input string, d *Debugger) object.Object {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	prog := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return evaluator.EvalContext(context.Background(), prog, object.NewEnvironment(), evaluator.Options{Hook: d})
+}
+
+func TestDebugger(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   bool
+		lines   []int
+		actions []Action
+		stops   []string
+	}{
+		{
+			name:  "breakpoints",
+			lines: []int{2, 7},
+			stops: []string{
+				"2 breakpoint add<<main>",
+				"7 breakpoint <main>",
+				"2 breakpoint add<<main>",
+			},
+		},
+		{
+			name:    "step into",
+			entry:   true,
+			actions: []Action{StepInto, StepInto, StepInto, StepInto, StepInto, Continue},
+			stops: []string{
+				"1 entry <main>",
+				"5 step <main>",
+				"6 step <main>",
+				"2 step add<<main>",
+				"3 step add<<main>",
+				"7 step <main>",
+			},
+		},
+		{
+			name:    "step over",
+			entry:   true,
+			actions: []Action{StepOver, StepOver, StepOver, StepOver, Continue},
+			stops: []string{
+				"1 entry <main>",
+				"5 step <main>",
+				"6 step <main>",
+				"7 step <main>",
+				"8 step <main>",
+			},
+		},
+		{
+			name:    "step out",
+			lines:   []int{2},
+			actions: []Action{StepOut, Quit},
+			stops: []string{
+				"2 breakpoint add<<main>",
+				"7 step <main>",
+			},
+		},
+		{
+			name:    "breakpoint while stepping over",
+			entry:   true,
+			lines:   []int{3},
+			actions: []Action{StepOver, StepOver, StepOver, Quit},
+			stops: []string{
+				"1 entry <main>",
+				"5 step <main>",
+				"6 step <main>",
+				"3 breakpoint add<<main>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontend := &scripted{actions: tt.actions}
+			d := New(frontend)
+			if tt.entry {
+				d.StopOnEntry()
+			}
+			for _, line := range tt.lines {
+				d.SetBreakpoint("", line)
+			}
+
+			debugEval(t, program, d)
+
+			if strings.Join(frontend.stops, "\n") != strings.Join(tt.stops, "\n") {
+				t.Errorf("wrong stops.\nexpected=%q\ngot=     %q", tt.stops, frontend.stops)
+			}
+		})
+	}
+}
+
+func TestDebuggerQuit(t *testing.T) {
+	d := New(&scripted{actions: []Action{Quit}})
+	d.StopOnEntry()
+
+	result := debugEval(t, program, d)
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", result, result)
+	}
+	if !errors.Is(errObj, ErrQuit) {
+		t.Errorf("wrong cause. got=%v", errObj.Cause)
+	}
+}
+
+func TestDebuggerSameLine(t *testing.T) {
+	// 한 줄에 있는 여러 문에서는 한 번만 멈추지만 재귀 호출마다 다시 멈춘다
+	input := `let f = fn(n) { if (n > 0) { f(n - 1) } else { 0 } };
+let a = 1; let b = f(2);`
+
+	frontend := &scripted{}
+	d := New(frontend)
+	d.SetBreakpoint("", 1)
+	d.SetBreakpoint("", 2)
+	debugEval(t, input, d)
+
+	expected := []string{
+		"1 breakpoint <main>",
+		"2 breakpoint <main>",
+		"1 breakpoint f<<main>",
+		"1 breakpoint f<f<<main>",
+		"1 breakpoint f<f<f<<main>",
+	}
+	if strings.Join(frontend.stops, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong stops.\nexpected=%q\ngot=     %q", expected, frontend.stops)
+	}
+}
+
+func TestBreakpoints(t *testing.T) {
+	d := New(&scripted{})
+	d.SetBreakpoint("", 7)
+	d.SetBreakpoint("", 2)
+	d.SetBreakpoint("a.mk", 1)
+
+	abs, _ := filepath.Abs("a.mk")
+	expected := []Breakpoint{{"", 2}, {"", 7}, {abs, 1}}
+	if fmt.Sprint(d.Breakpoints()) != fmt.Sprint(expected) {
+		t.Errorf("wrong breakpoints. expected=%v, got=%v", expected, d.Breakpoints())
+	}
+
+	if !d.ClearBreakpoint("", 7) {
+		t.Errorf("ClearBreakpoint returned false")
+	}
+	if d.ClearBreakpoint("", 7) {
+		t.Errorf("ClearBreakpoint of a deleted breakpoint returned true")
+	}
+	d.ClearBreakpoints("a.mk")
+	if len(d.Breakpoints()) != 1 {
+		t.Errorf("wrong breakpoints after clearing. got=%v", d.Breakpoints())
+	}
+}
+
+func TestConsole(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.mk")
+	if err := os.WriteFile(file, []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// print 의 import 는 현재 작업 디렉터리가 아닌 프레임의 파일을 기준으로 찾는다
+	if err := os.WriteFile(filepath.Join(dir, "lib.mk"), []byte("let v = 42;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := []string{
+		"break 3",
+		"breakpoints",
+		"continue",
+		"stack",
+		"locals",
+		"frame 1",
+		"locals",
+		"print x + 10",
+		`print import "lib.mk"["v"]`,
+		"next",
+		"out",
+		"bogus",
+		"quit",
+	}
+	var out strings.Builder
+	console := NewConsole(strings.NewReader(strings.Join(commands, "\n")), &out)
+	d := New(console)
+	d.StopOnEntry()
+
+	p := parser.New(lexer.New(program))
+	result := evaluator.EvalContext(context.Background(), p.ParseProgram(), object.NewEnvironment(),
+		evaluator.Options{Hook: d, Filename: file})
+	if !errors.Is(result.(*object.Error), ErrQuit) {
+		t.Fatalf("wrong result. got=%s", result.Inspect())
+	}
+
+	expected := `stopped at main.mk:1:1 (entry) in <main>
+>    1  let add = fn(a, b) {
+(debug) breakpoint set at main.mk:3
+(debug) main.mk:3
+(debug) stopped at main.mk:3:3 (breakpoint) in add
+>    3    sum
+(debug) *#0 add at main.mk:3:3
+ #1 <main> at main.mk:6:1
+(debug) a = 1
+b = 2
+sum = 3
+(debug) #1 <main> at main.mk:6:1
+(debug) add = fn(a, b) { let sum = (a + b); sum }
+x = 1
+(debug) 11
+(debug) 42
+(debug) stopped at main.mk:7:1 (step) in <main>
+>    7  let z = add(y, 3);
+(debug) stopped at main.mk:3:3 (breakpoint) in add
+>    3    sum
+(debug) unknown command "bogus"; type help for a list of commands
+(debug) `
+	if out.String() != expected {
+		t.Errorf("wrong console output.\nexpected=%q\ngot=     %q", expected, out.String())
+	}
+}
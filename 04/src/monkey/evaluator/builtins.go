@@ -1,8 +1,10 @@
 package evaluator
 
 import (
-	"fmt"
+	"io"
 	"monkey/object"
+	"os"
+	"sort"
 )
 
 // 내장함수를 모아둔 map 객체
@@ -20,18 +22,26 @@ var builtins = map[string]*object.Builtin{
 		case *object.String:
 			// String 타입이라면 len 은 문자열의 길이를 반환
 			return &object.Integer{Value: int64(len(arg.Value))}
+		case *object.Hash:
+			// Hash 타입이라면 len 은 키-값 쌍의 개수를 반환
+			return &object.Integer{Value: int64(arg.Len())}
 		default:
-			// 내장 함수 len 이 지원하는 타입은 String, Array 뿐이다
+			// 내장 함수 len 이 지원하는 타입은 String, Array, Hash 뿐이다
 			return newError("argument to `len` not supported, got %s",
 				args[0].Type())
 		}
 	},
 	},
 	// 내장함수 puts 는 인자로 받은 객체를 출력한다. (출력만 하고 값을 만들어내지 않으므로 반환 값은 NULL)
+	// 출력은 Options.Stdout 으로 보내므로 import 한 모듈의 puts 도 같은 곳으로 출력된다
 	"puts": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Callback: func(applier object.Applier, args ...object.Object) object.Object {
+			var w io.Writer = os.Stdout
+			if s, ok := applier.(*state); ok && s.stdout != nil {
+				w = s.stdout
+			}
 			for _, arg := range args {
-				fmt.Println(arg.Inspect())
+				io.WriteString(w, arg.Inspect()+"\n")
 			}
 
 			return NULL
@@ -130,4 +140,59 @@ var builtins = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	// 문자열을 다루는 내장함수들 (builtins_strings.go)
+	"split":       &object.Builtin{Fn: splitBuiltin},
+	"join":        &object.Builtin{Callback: joinBuiltin},
+	"trim":        &object.Builtin{Fn: trimBuiltin},
+	"contains":    &object.Builtin{Fn: containsBuiltin},
+	"index_of":    &object.Builtin{Fn: indexOfBuiltin},
+	"replace":     &object.Builtin{Callback: replaceBuiltin},
+	"upper":       &object.Builtin{Fn: upperBuiltin},
+	"lower":       &object.Builtin{Fn: lowerBuiltin},
+	"starts_with": &object.Builtin{Fn: startsWithBuiltin},
+	"ends_with":   &object.Builtin{Fn: endsWithBuiltin},
+	"repeat":      &object.Builtin{Callback: repeatBuiltin},
+	"format":      &object.Builtin{Fn: formatBuiltin},
+	"chars":       &object.Builtin{Fn: charsBuiltin},
+	// 배열을 다루는 내장함수들 (builtins_arrays.go). contains 와 index_of 는 배열에도 사용할 수 있다
+	"map":     &object.Builtin{Callback: mapBuiltin},
+	"filter":  &object.Builtin{Callback: filterBuiltin},
+	"reduce":  &object.Builtin{Callback: reduceBuiltin},
+	"sort":    &object.Builtin{Callback: sortBuiltin},
+	"reverse": &object.Builtin{Fn: reverseBuiltin},
+	"slice":   &object.Builtin{Fn: sliceBuiltin},
+	"concat":  &object.Builtin{Fn: concatBuiltin},
+	"zip":     &object.Builtin{Fn: zipBuiltin},
+	"flatten": &object.Builtin{Fn: flattenBuiltin},
+	"range":   &object.Builtin{Callback: rangeBuiltin},
+	// 해시를 다루는 내장함수들 (builtins_hashes.go)
+	"keys":   &object.Builtin{Fn: keysBuiltin},
+	"values": &object.Builtin{Fn: valuesBuiltin},
+	"items":  &object.Builtin{Fn: itemsBuiltin},
+	"has":    &object.Builtin{Fn: hasBuiltin},
+	"delete": &object.Builtin{Fn: deleteBuiltin},
+	"merge":  &object.Builtin{Fn: mergeBuiltin},
+	// 동시성을 위한 내장함수들 (concurrency.go)
+	"spawn":   &object.Builtin{Callback: spawnBuiltin},
+	"await":   &object.Builtin{Callback: awaitBuiltin},
+	"cancel":  &object.Builtin{Fn: cancelBuiltin},
+	"channel": &object.Builtin{Fn: channelBuiltin},
+	"send":    &object.Builtin{Callback: sendBuiltin},
+	"recv":    &object.Builtin{Callback: recvBuiltin},
+	"close":   &object.Builtin{Fn: closeBuiltin},
+	"select":  &object.Builtin{Callback: selectBuiltin},
+	// 테스트를 위한 단언 내장함수들 (builtins_assert.go)
+	"assert":       &object.Builtin{Fn: assertBuiltin},
+	"assert_eq":    &object.Builtin{Fn: assertEqBuiltin},
+	"assert_error": &object.Builtin{Callback: assertErrorBuiltin},
+}
+
+// BuiltinNames 는 모든 내장함수의 이름을 정렬하여 반환한다
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
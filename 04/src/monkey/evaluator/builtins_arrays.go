@@ -0,0 +1,462 @@
+package evaluator
+
+import (
+	"math"
+	"monkey/object"
+	"sort"
+)
+
+// 배열을 다루는 고차 함수들. 인자로 받은 함수는 object.Applier 를 통해 호출하므로
+// 배열의 길이와 상관없이 Monkey 의 재귀 깊이 제한에 걸리지 않는다.
+// 인자로 받은 배열은 바꾸지 않고 항상 새로운 배열을 반환한다.
+
+// mapBuiltin 은 map(arr, fn) 으로 각 원소에 fn 을 적용한 결과를 담은 배열을 반환한다
+func mapBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	arr, fn, err := arrayAndFunctionArguments("map", args)
+	if err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	for i, e := range arr.Elements {
+		result := applier.Apply(fn, e)
+		if isError(result) {
+			return result
+		}
+		elements[i] = result
+	}
+	return &object.Array{Elements: elements}
+}
+
+// filterBuiltin 은 filter(arr, fn) 으로 fn 의 결과가 참인 원소만 담은 배열을 반환한다
+func filterBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	arr, fn, err := arrayAndFunctionArguments("filter", args)
+	if err != nil {
+		return err
+	}
+
+	elements := []object.Object{}
+	for _, e := range arr.Elements {
+		result := applier.Apply(fn, e)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			elements = append(elements, e)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// reduceBuiltin 은 reduce(arr, fn, initial) 로 누적 값과 각 원소에 fn(acc, e) 를 차례로 적용한 결과를 반환한다.
+// initial 을 생략하면 첫 번째 원소를 누적 값의 초기값으로 사용한다
+func reduceBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3",
+			len(args))
+	}
+	arr, fn, err := arrayAndFunctionArguments("reduce", args[:2])
+	if err != nil {
+		return err
+	}
+
+	elements := arr.Elements
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("reduce of empty array with no initial value")
+		}
+		acc, elements = elements[0], elements[1:]
+	}
+
+	for _, e := range elements {
+		acc = applier.Apply(fn, acc, e)
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+// sortBuiltin 은 sort(arr) 로 정수나 문자열 배열을 오름차순으로 정렬한 배열을 반환한다.
+// sort(arr, fn) 처럼 비교 함수를 넘기면 fn(a, b) 가 참일 때 a 를 b 보다 앞에 둔다. 정렬은 안정적이다
+func sortBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `sort` must be ARRAY, got %s",
+			args[0].Type())
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	copy(elements, arr.Elements)
+
+	// 비교 중에 발생한 첫 번째 에러. 에러가 발생한 뒤에는 더 이상 비교 함수를 호출하지 않는다
+	var sortErr object.Object
+	var less func(a, b object.Object) bool
+
+	if len(args) == 2 {
+		fn := args[1]
+		if !isFunction(fn) {
+			return newError("argument to `sort` must be FUNCTION, got %s",
+				fn.Type())
+		}
+		less = func(a, b object.Object) bool {
+			result := applier.Apply(fn, a, b)
+			if isError(result) {
+				sortErr = result
+				return false
+			}
+			if result.Type() != object.BOOLEAN_OBJ {
+				sortErr = newError("comparator passed to `sort` must return BOOLEAN, got %s",
+					result.Type())
+				return false
+			}
+			return result == TRUE
+		}
+	} else {
+		less = func(a, b object.Object) bool {
+			result, ok := compareObjects(a, b)
+			if !ok {
+				sortErr = newError("cannot compare %s and %s in `sort`",
+					a.Type(), b.Type())
+				return false
+			}
+			return result < 0
+		}
+	}
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		return less(elements[i], elements[j])
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+	return &object.Array{Elements: elements}
+}
+
+// reverseBuiltin 은 reverse(x) 로 배열의 원소나 문자열의 문자를 거꾸로 뒤집는다
+func reverseBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Array:
+		length := len(arg.Elements)
+		elements := make([]object.Object, length)
+		for i, e := range arg.Elements {
+			elements[length-1-i] = e
+		}
+		return &object.Array{Elements: elements}
+	case *object.String:
+		runes := []rune(arg.Value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return &object.String{Value: string(runes)}
+	default:
+		return newError("argument to `reverse` must be ARRAY or STRING, got %s",
+			args[0].Type())
+	}
+}
+
+// sliceBuiltin 은 slice(x, start) 나 slice(x, start, end) 로 배열이나 문자열의 [start, end) 구간을 반환한다.
+// 음수 인덱스는 끝에서부터 센다. 범위를 벗어난 인덱스는 양 끝으로 맞춘다
+func sliceBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3",
+			len(args))
+	}
+
+	var length int
+	switch arg := args[0].(type) {
+	case *object.Array:
+		length = len(arg.Elements)
+	case *object.String:
+		length = len(arg.Value)
+	default:
+		return newError("argument to `slice` must be ARRAY or STRING, got %s",
+			args[0].Type())
+	}
+
+	bounds := []int{0, length}
+	for i, arg := range args[1:] {
+		index, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument to `slice` must be INTEGER, got %s",
+				arg.Type())
+		}
+		bounds[i] = clampIndex(index.Value, length)
+	}
+	start, end := bounds[0], bounds[1]
+	if end < start {
+		end = start
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Array:
+		elements := make([]object.Object, end-start)
+		copy(elements, arg.Elements[start:end])
+		return &object.Array{Elements: elements}
+	default:
+		return &object.String{Value: arg.(*object.String).Value[start:end]}
+	}
+}
+
+// clampIndex 는 음수 인덱스를 끝에서부터의 위치로 바꾸고 [0, length] 범위로 맞춘다
+func clampIndex(index int64, length int) int {
+	if index < 0 {
+		index += int64(length)
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > int64(length) {
+		return length
+	}
+	return int(index)
+}
+
+// concatBuiltin 은 concat(a, b, ...) 로 배열들이나 문자열들을 이어 붙인다
+func concatBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want at least 1",
+			len(args))
+	}
+
+	switch args[0].(type) {
+	case *object.Array:
+		elements := []object.Object{}
+		for _, arg := range args {
+			arr, ok := arg.(*object.Array)
+			if !ok {
+				return newError("argument to `concat` must be ARRAY, got %s",
+					arg.Type())
+			}
+			elements = append(elements, arr.Elements...)
+		}
+		return &object.Array{Elements: elements}
+	case *object.String:
+		value := ""
+		for _, arg := range args {
+			str, ok := arg.(*object.String)
+			if !ok {
+				return newError("argument to `concat` must be STRING, got %s",
+					arg.Type())
+			}
+			value += str.Value
+		}
+		return &object.String{Value: value}
+	default:
+		return newError("argument to `concat` must be ARRAY or STRING, got %s",
+			args[0].Type())
+	}
+}
+
+// zipBuiltin 은 zip(a, b, ...) 로 각 배열에서 같은 위치의 원소들을 묶은 배열들을 반환한다.
+// 결과의 길이는 가장 짧은 배열의 길이이다
+func zipBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want at least 1",
+			len(args))
+	}
+
+	arrays := make([]*object.Array, len(args))
+	length := -1
+	for i, arg := range args {
+		arr, ok := arg.(*object.Array)
+		if !ok {
+			return newError("argument to `zip` must be ARRAY, got %s",
+				arg.Type())
+		}
+		arrays[i] = arr
+		if length < 0 || len(arr.Elements) < length {
+			length = len(arr.Elements)
+		}
+	}
+
+	elements := make([]object.Object, length)
+	for i := range elements {
+		tuple := make([]object.Object, len(arrays))
+		for j, arr := range arrays {
+			tuple[j] = arr.Elements[i]
+		}
+		elements[i] = &object.Array{Elements: tuple}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// flattenBuiltin 은 flatten(arr) 로 한 단계, flatten(arr, depth) 로 depth 단계만큼 중첩된 배열을 펼친다
+func flattenBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `flatten` must be ARRAY, got %s",
+			args[0].Type())
+	}
+
+	depth := int64(1)
+	if len(args) == 2 {
+		d, ok := args[1].(*object.Integer)
+		if !ok {
+			return newError("argument to `flatten` must be INTEGER, got %s",
+				args[1].Type())
+		}
+		depth = d.Value
+	}
+
+	return &object.Array{Elements: flatten([]object.Object{}, arr.Elements, depth)}
+}
+
+func flatten(dst, elements []object.Object, depth int64) []object.Object {
+	for _, e := range elements {
+		if inner, ok := e.(*object.Array); ok && depth > 0 {
+			dst = flatten(dst, inner.Elements, depth-1)
+		} else {
+			dst = append(dst, e)
+		}
+	}
+	return dst
+}
+
+// rangeBuiltin 은 range(end), range(start, end), range(start, end, step) 으로 [start, end) 구간의 정수 배열을 만든다.
+// 원소의 개수를 먼저 계산하여 배열을 할당하기 전에 실행 제한을 확인하고, 원소를 만드는 일도 단계로 센다
+func rangeBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("wrong number of arguments. got=%d, want=1 to 3",
+			len(args))
+	}
+
+	values := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument to `range` must be INTEGER, got %s",
+				arg.Type())
+		}
+		values[i] = integer.Value
+	}
+
+	start, end, step := int64(0), values[0], int64(1)
+	if len(values) >= 2 {
+		start, end = values[0], values[1]
+	}
+	if len(values) == 3 {
+		step = values[2]
+	}
+	if step == 0 {
+		return newError("range step must not be zero")
+	}
+
+	count := rangeLength(start, end, step)
+	s, ok := applier.(*state)
+	if ok {
+		if err := s.reserve(object.ARRAY_OBJ, int64(min(count, math.MaxInt64))); err != nil {
+			return err
+		}
+	}
+
+	elements := make([]object.Object, 0, min(count, 1<<16))
+	for k := uint64(0); k < count; k++ {
+		if ok && k%contextCheckInterval == 0 {
+			if err := s.stepN(int64(min(count-k, contextCheckInterval))); err != nil {
+				return err
+			}
+		}
+		elements = append(elements, &object.Integer{Value: start + int64(k)*step})
+	}
+	return &object.Array{Elements: elements}
+}
+
+// rangeLength 는 range(start, end, step) 이 만드는 원소의 개수를 반환한다.
+// end - start 가 int64 를 넘칠 수 있으므로 부호 없는 정수로 계산한다
+func rangeLength(start, end, step int64) uint64 {
+	switch {
+	case step > 0 && start < end:
+		return (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	case step < 0 && start > end:
+		return (uint64(start)-uint64(end)-1)/(-uint64(step)) + 1
+	}
+	return 0
+}
+
+// arrayIndexOf 는 arr 에서 value 와 같은 원소가 처음 나타나는 위치를 반환한다. 없다면 -1 을 반환한다
+func arrayIndexOf(arr *object.Array, value object.Object) int {
+	for i, e := range arr.Elements {
+		if object.Equal(e, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareObjects 는 같은 타입의 정수나 문자열, 또는 Comparable 객체의 순서를 비교한다
+func compareObjects(a, b object.Object) (int, bool) {
+	switch a := a.(type) {
+	case *object.Integer:
+		if b, ok := b.(*object.Integer); ok {
+			switch {
+			case a.Value < b.Value:
+				return -1, true
+			case a.Value > b.Value:
+				return 1, true
+			}
+			return 0, true
+		}
+	case *object.String:
+		if b, ok := b.(*object.String); ok {
+			switch {
+			case a.Value < b.Value:
+				return -1, true
+			case a.Value > b.Value:
+				return 1, true
+			}
+			return 0, true
+		}
+	case object.Comparable:
+		return a.Compare(b)
+	}
+	return 0, false
+}
+
+// arrayAndFunctionArguments 는 name 내장함수가 배열과 함수를 인자로 받았는지 확인한다
+func arrayAndFunctionArguments(
+	name string,
+	args []object.Object,
+) (*object.Array, object.Object, *object.Error) {
+	if len(args) != 2 {
+		return nil, nil, newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, nil, newError("argument to `%s` must be ARRAY, got %s",
+			name, args[0].Type())
+	}
+	if !isFunction(args[1]) {
+		return nil, nil, newError("argument to `%s` must be FUNCTION, got %s",
+			name, args[1].Type())
+	}
+	return arr, args[1], nil
+}
+
+func isFunction(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, object.Callable:
+		return true
+	}
+	return false
+}
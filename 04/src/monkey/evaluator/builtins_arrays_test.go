@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"context"
+	"monkey/object"
+	"testing"
+)
+
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, []int{2, 4, 6}},
+		{`map([], fn(x) { x * 2 })`, []int{}},
+		{`map(["a", "bb"], len)`, []int{1, 2}},
+		{`filter([1, 2, 3, 4], fn(x) { x > 2 })`, []int{3, 4}},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc + x }, 10)`, 20},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc * x })`, 24},
+		{`reduce([], fn(acc, x) { acc + x }, 0)`, 0},
+		{`sort([3, 1, 2])`, []int{1, 2, 3}},
+		{`sort([3, 1, 2], fn(a, b) { a > b })`, []int{3, 2, 1}},
+		{`sort(["b", "c", "a"])[0]`, "a"},
+		{`let a = [3, 1, 2]; sort(a); a`, []int{3, 1, 2}},
+		{`reverse([1, 2, 3])`, []int{3, 2, 1}},
+		{`reverse("héllo")`, "olléh"},
+		{`slice([1, 2, 3, 4], 1)`, []int{2, 3, 4}},
+		{`slice([1, 2, 3, 4], 1, 3)`, []int{2, 3}},
+		{`slice([1, 2, 3, 4], -2)`, []int{3, 4}},
+		{`slice([1, 2, 3, 4], 3, 1)`, []int{}},
+		{`slice([1, 2, 3, 4], 0, 100)`, []int{1, 2, 3, 4}},
+		{`slice("monkey", 1, -1)`, "onke"},
+		{`concat([1], [2, 3], [])`, []int{1, 2, 3}},
+		{`concat("mon", "key")`, "monkey"},
+		{`contains([1, 2, 3], 2)`, true},
+		{`contains([1, 2, 3], 4)`, false},
+		{`contains(["a", "b"], "b")`, true},
+		{`index_of([1, 2, 3], 3)`, 2},
+		{`index_of([1, 2, 3], 4)`, -1},
+		{`len(zip([1, 2, 3], ["a", "b"]))`, 2},
+		{`zip([1, 2], [3, 4])[1]`, []int{2, 4}},
+		{`flatten([[1, 2], [3], [], 4])`, []int{1, 2, 3, 4}},
+		{`len(flatten([[1, [2, [3]]]]))`, 2},
+		{`flatten([[1, [2, [3]]]], 10)`, []int{1, 2, 3}},
+		{`range(4)`, []int{0, 1, 2, 3}},
+		{`range(2, 5)`, []int{2, 3, 4}},
+		{`range(10, 0, -3)`, []int{10, 7, 4, 1}},
+		{`range(0)`, []int{}},
+		{`range(5, 0)`, []int{}},
+		{`range(9223372036854775805, 9223372036854775807)`, []int{9223372036854775805, 9223372036854775806}},
+		{`range(-9223372036854775807, 9223372036854775807, 4611686018427387904)`,
+			[]int{-9223372036854775807, -4611686018427387903, 1, 4611686018427387905}},
+		{
+			`reduce(map(filter(range(10), fn(x) { x - x / 2 * 2 == 1 }), fn(x) { x * x }), fn(a, b) { a + b })`,
+			165,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testStringObject(t, evaluated, expected)
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements for %q. want=%d, got=%d",
+					tt.input, len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		}
+	}
+}
+
+func TestArrayBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`map([1])`, "wrong number of arguments. got=1, want=2"},
+		{`map(1, fn(x) { x })`, "argument to `map` must be ARRAY, got INTEGER"},
+		{`map([1], 1)`, "argument to `map` must be FUNCTION, got INTEGER"},
+		{`map([1, 2], fn(x) { x + true })`, "type mismatch: INTEGER + BOOLEAN"},
+		{`map([1], fn(a, b) { a })`, "wrong number of arguments. got=1, want=2"},
+		{`filter([1], fn(x) { y })`, "identifier not found: y"},
+		{`reduce([], fn(a, b) { a + b })`, "reduce of empty array with no initial value"},
+		{`reduce([1], fn(a, b) { a + b }, 0, 1)`, "wrong number of arguments. got=4, want=2 or 3"},
+		{`sort([1, "a"])`, "cannot compare STRING and INTEGER in `sort`"},
+		{`sort([1, 2], fn(a, b) { 1 })`, "comparator passed to `sort` must return BOOLEAN, got INTEGER"},
+		{`sort([1, 2], 1)`, "argument to `sort` must be FUNCTION, got INTEGER"},
+		{`sort(1)`, "argument to `sort` must be ARRAY, got INTEGER"},
+		{`reverse(1)`, "argument to `reverse` must be ARRAY or STRING, got INTEGER"},
+		{`slice([1], "a")`, "argument to `slice` must be INTEGER, got STRING"},
+		{`concat([1], "a")`, "argument to `concat` must be ARRAY, got STRING"},
+		{`concat(1)`, "argument to `concat` must be ARRAY or STRING, got INTEGER"},
+		{`zip([1], 1)`, "argument to `zip` must be ARRAY, got INTEGER"},
+		{`flatten(1)`, "argument to `flatten` must be ARRAY, got INTEGER"},
+		{`range()`, "wrong number of arguments. got=0, want=1 to 3"},
+		{`range(0, 10, 0)`, "range step must not be zero"},
+		{`range("a")`, "argument to `range` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)",
+				tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestArrayBuiltinsAreNotRecursive(t *testing.T) {
+	// 내장 함수는 원소마다 콜백을 호출할 뿐 재귀하지 않으므로 배열의 길이가 호출 깊이 제한에 걸리지 않는다
+	input := `reduce(map(range(10000), fn(x) { x * 2 }), fn(a, b) { a + b })`
+
+	evaluated := testEvalContext(context.Background(), input, Options{MaxDepth: 10})
+	testIntegerObject(t, evaluated, 99990000)
+}
@@ -0,0 +1,264 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/object"
+	"strconv"
+	"strings"
+)
+
+// 테스트를 위한 단언(assertion) 내장함수들. 단언이 실패하면 Cause 가 ErrAssertion 인 에러 객체를 반환하므로
+// 테스트 러너는 errors.Is 로 단언의 실패와 그 밖의 런타임 에러를 구분할 수 있다.
+
+// ErrAssertion 은 단언이 실패했을 때 반환되는 에러 객체의 Cause 이다.
+var ErrAssertion = errors.New("assertion failed")
+
+// maxDifferences 는 assert_eq 가 보여주는 차이의 최대 개수
+const maxDifferences = 10
+
+// maxLineTable 은 lineDifference 가 최장 공통 부분 수열을 구하는 표의 최대 크기.
+// 두 문자열의 줄 수를 곱한 값이 이보다 크면 처음으로 다른 줄만 보여준다
+const maxLineTable = 1 << 20
+
+// stopErrors 는 평가를 멈춘 에러들이다. 단언의 대상이 아니므로 assert_error 는 이 에러들을 잡지 않고 그대로 반환한다
+var stopErrors = []error{
+	ErrMaxDepth, ErrMaxSteps, ErrMaxAllocations, ErrMaxCollectionSize,
+	context.Canceled, context.DeadlineExceeded,
+	ErrHookStopped, // debug.ErrQuit 처럼 Hook 이 평가를 멈춘 경우
+}
+
+// assertionError 는 name 의 단언이 실패했을 때의 에러 객체를 만든다.
+// message 가 있다면 첫 줄에 덧붙이고, details 는 그 아래에 한 줄씩 들여써서 보여준다
+func assertionError(name string, message []object.Object, details ...string) *object.Error {
+	var b strings.Builder
+	b.WriteString(name + " failed")
+	if len(message) > 0 {
+		b.WriteString(": " + message[0].Inspect())
+	}
+	for _, d := range details {
+		b.WriteString("\n  " + d)
+	}
+	return &object.Error{Message: b.String(), Cause: ErrAssertion}
+}
+
+// assertBuiltin 은 assert(cond) 또는 assert(cond, message) 로 cond 가 참인지 확인한다
+func assertBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+	if !isTruthy(args[0]) {
+		return assertionError("assert", args[1:], "got: "+literal(args[0]))
+	}
+	return NULL
+}
+
+// assertEqBuiltin 은 assert_eq(actual, expected) 또는 assert_eq(actual, expected, message) 로
+// 두 값이 == 로 같은지 확인한다. 다르다면 두 값과 함께 배열과 해시의 어느 원소가 다른지를 보여준다
+func assertEqBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+	actual, expected := args[0], args[1]
+	if object.Equal(actual, expected) {
+		return NULL
+	}
+
+	details := []string{"actual:   " + literal(actual), "expected: " + literal(expected)}
+	diffs := difference(nil, "", actual, expected)
+	if len(diffs) > maxDifferences {
+		diffs = append(diffs[:maxDifferences], fmt.Sprintf("... and %d more differences", len(diffs)-maxDifferences))
+	}
+	details = append(details, diffs...)
+	return assertionError("assert_eq", args[2:], details...)
+}
+
+// assertErrorBuiltin 은 assert_error(fn) 또는 assert_error(fn, substr) 로 인자 없이 호출한 fn 이
+// 에러로 끝나는지 확인하고, 에러 메시지를 문자열로 반환한다. substr 이 주어지면 에러 메시지가 substr 을 포함해야 한다.
+// 실행 제한이나 취소, Hook 으로 멈춘 경우는 단언의 대상이 아니므로 그 에러를 그대로 반환한다
+func assertErrorBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+	if !isFunction(args[0]) {
+		return newError("first argument to `assert_error` must be FUNCTION, got %s", args[0].Type())
+	}
+	if len(args) == 2 && args[1].Type() != object.STRING_OBJ {
+		return newError("second argument to `assert_error` must be STRING, got %s", args[1].Type())
+	}
+
+	result := applier.Apply(args[0])
+	err, ok := result.(*object.Error)
+	if !ok {
+		return assertionError("assert_error", nil, "expected an error, got: "+literal(result))
+	}
+	for _, stop := range stopErrors {
+		if errors.Is(err, stop) {
+			return err
+		}
+	}
+
+	if len(args) == 2 {
+		substr := args[1].(*object.String).Value
+		if !strings.Contains(err.Message, substr) {
+			return assertionError("assert_error", nil,
+				"error:    "+strconv.Quote(err.Message),
+				"expected: error containing "+strconv.Quote(substr))
+		}
+	}
+	return &object.String{Value: err.Message}
+}
+
+// literal 은 obj 를 Monkey 리터럴처럼 보여준다. Inspect 와 달리 문자열을 따옴표로 감싸서 1 과 "1" 을 구분한다
+func literal(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.String:
+		return strconv.Quote(obj.Value)
+	case *object.Array:
+		elements := make([]string, len(obj.Elements))
+		for i, e := range obj.Elements {
+			elements[i] = literal(e)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *object.Hash:
+		pairs := make([]string, 0, obj.Len())
+		for _, pair := range obj.Pairs() {
+			pairs = append(pairs, literal(pair.Key)+": "+literal(pair.Value))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	case nil:
+		return "null"
+	}
+	return obj.Inspect()
+}
+
+// difference 는 actual 과 expected 가 다른 곳들을 diffs 에 덧붙인다. path 는 비교하는 값에 이르는 인덱스 식이다.
+// 배열과 해시는 원소마다 재귀적으로 비교하고, 여러 줄의 문자열은 줄 단위로 비교한다.
+// 두 값 자체는 이미 보여주었으므로 path 가 빈 문자열인 가장 바깥의 값이 다르다는 것은 덧붙이지 않는다
+func difference(diffs []string, path string, actual, expected object.Object) []string {
+	at := func(format string, a ...interface{}) []string {
+		if path == "" {
+			return diffs
+		}
+		return append(diffs, "at "+path+": "+fmt.Sprintf(format, a...))
+	}
+
+	switch actual := actual.(type) {
+	case *object.Array:
+		expected, ok := expected.(*object.Array)
+		if !ok {
+			break
+		}
+		n := len(actual.Elements)
+		if len(expected.Elements) < n {
+			n = len(expected.Elements)
+		}
+		for i := 0; i < n; i++ {
+			diffs = difference(diffs, fmt.Sprintf("%s[%d]", path, i), actual.Elements[i], expected.Elements[i])
+		}
+		for i := n; i < len(actual.Elements); i++ {
+			diffs = append(diffs, fmt.Sprintf("at %s[%d]: unexpected %s", path, i, literal(actual.Elements[i])))
+		}
+		for i := n; i < len(expected.Elements); i++ {
+			diffs = append(diffs, fmt.Sprintf("at %s[%d]: missing %s", path, i, literal(expected.Elements[i])))
+		}
+		return diffs
+	case *object.Hash:
+		expected, ok := expected.(*object.Hash)
+		if !ok {
+			break
+		}
+		for _, pair := range actual.Pairs() {
+			key := fmt.Sprintf("%s[%s]", path, literal(pair.Key))
+			value, ok := expected.Get(pair.Key.(object.Hashable))
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("at %s: unexpected %s", key, literal(pair.Value)))
+				continue
+			}
+			diffs = difference(diffs, key, pair.Value, value)
+		}
+		for _, pair := range expected.Pairs() {
+			if _, ok := actual.Get(pair.Key.(object.Hashable)); !ok {
+				diffs = append(diffs, fmt.Sprintf("at %s[%s]: missing %s", path, literal(pair.Key), literal(pair.Value)))
+			}
+		}
+		return diffs
+	case *object.String:
+		expected, ok := expected.(*object.String)
+		if !ok || actual.Value == expected.Value {
+			break
+		}
+		if strings.Contains(actual.Value, "\n") || strings.Contains(expected.Value, "\n") {
+			return lineDifference(diffs, path, actual.Value, expected.Value)
+		}
+	}
+
+	if object.Equal(actual, expected) {
+		return diffs
+	}
+	if actual.Type() != expected.Type() {
+		return at("actual %s %s, expected %s %s", actual.Type(), literal(actual), expected.Type(), literal(expected))
+	}
+	return at("actual %s, expected %s", literal(actual), literal(expected))
+}
+
+// lineDifference 는 여러 줄의 문자열에서 다른 줄들을 최장 공통 부분 수열로 찾아
+// expected 에만 있는 줄은 "-" 와 expected 에서의 줄 번호로, actual 에만 있는 줄은 "+" 와 actual 에서의 줄 번호로 표시한다
+func lineDifference(diffs []string, path string, actual, expected string) []string {
+	a, e := strings.Split(actual, "\n"), strings.Split(expected, "\n")
+
+	prefix := ""
+	if path != "" {
+		prefix = "at " + path + ": "
+	}
+	if int64(len(a))*int64(len(e)) > maxLineTable {
+		return firstLineDifference(diffs, prefix, a, e)
+	}
+
+	// lcs[i][j] 는 a[i:] 와 e[j:] 의 최장 공통 부분 수열의 길이
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(e)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(e) - 1; j >= 0; j-- {
+			if a[i] == e[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(e) {
+		switch {
+		case i < len(a) && j < len(e) && a[i] == e[j]:
+			i++
+			j++
+		case j < len(e) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			diffs = append(diffs, fmt.Sprintf("%sline %d: - %s", prefix, j+1, strconv.Quote(e[j])))
+			j++
+		default:
+			diffs = append(diffs, fmt.Sprintf("%sline %d: + %s", prefix, i+1, strconv.Quote(a[i])))
+			i++
+		}
+	}
+	return diffs
+}
+
+// firstLineDifference 는 표가 너무 커서 lineDifference 가 비교할 수 없는 문자열에서 처음으로 다른 줄만 보여준다
+func firstLineDifference(diffs []string, prefix string, a, e []string) []string {
+	i := 0
+	for i < len(a) && i < len(e) && a[i] == e[i] {
+		i++
+	}
+	if i < len(e) {
+		diffs = append(diffs, fmt.Sprintf("%sline %d: - %s", prefix, i+1, strconv.Quote(e[i])))
+	}
+	if i < len(a) {
+		diffs = append(diffs, fmt.Sprintf("%sline %d: + %s", prefix, i+1, strconv.Quote(a[i])))
+	}
+	return append(diffs, prefix+"(only the first differing line is shown)")
+}
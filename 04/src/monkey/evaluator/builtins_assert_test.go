@@ -0,0 +1,169 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAssertBuiltins(t *testing.T) {
+	// 성공한 단언은 NULL 을, assert_error 는 잡은 에러의 메시지를 반환한다
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`assert(true)`, "null"},
+		{`assert(1 < 2, "ordered")`, "null"},
+		{`assert_eq(1 + 1, 2)`, "null"},
+		{`assert_eq([1, {"a": [2]}], [1, {"a": [2]}], "nested")`, "null"},
+		{`assert_eq({"a": 1, "b": 2}, {"b": 2, "a": 1})`, "null"},
+		{`assert_error(fn() { 1 + true })`, "type mismatch: INTEGER + BOOLEAN"},
+		{`assert_error(fn() { 1 + true }, "mismatch")`, "type mismatch: INTEGER + BOOLEAN"},
+		{`assert_error(fn() { assert(false) })`, "assert failed\n  got: false"},
+		{`assert_error(fn() { first(1) }, "ARRAY")`, "argument to `first` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if isError(evaluated) {
+			t.Errorf("unexpected error for %q: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAssertFailures(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`assert(false)`, "assert failed\n  got: false"},
+		{`assert(if (false) { 1 }, "must be set")`, "assert failed: must be set\n  got: null"},
+		{`assert_eq(1, 2)`, "assert_eq failed\n  actual:   1\n  expected: 2"},
+		{`assert_eq(1, "1")`, "assert_eq failed\n  actual:   1\n  expected: \"1\""},
+		{`assert_eq("a", "b", "names")`, "assert_eq failed: names\n  actual:   \"a\"\n  expected: \"b\""},
+		{`assert_eq([1, 2, 3], [1, 5, 3])`,
+			"assert_eq failed\n  actual:   [1, 2, 3]\n  expected: [1, 5, 3]\n  at [1]: actual 2, expected 5"},
+		{`assert_eq([1], [1, "x"])`,
+			"assert_eq failed\n  actual:   [1]\n  expected: [1, \"x\"]\n  at [1]: missing \"x\""},
+		{`assert_eq([1, [2, 3]], [1, [2]])`,
+			"assert_eq failed\n  actual:   [1, [2, 3]]\n  expected: [1, [2]]\n  at [1][1]: unexpected 3"},
+		{`assert_eq({"a": 1, "b": [true]}, {"b": ["true"], "c": 3})`,
+			"assert_eq failed\n  actual:   {\"a\": 1, \"b\": [true]}\n  expected: {\"b\": [\"true\"], \"c\": 3}\n" +
+				"  at [\"a\"]: unexpected 1\n" +
+				"  at [\"b\"][0]: actual BOOLEAN true, expected STRING \"true\"\n" +
+				"  at [\"c\"]: missing 3"},
+		{"assert_eq(\"a\nb\nc\", \"a\nx\nc\")",
+			"assert_eq failed\n  actual:   \"a\\nb\\nc\"\n  expected: \"a\\nx\\nc\"\n" +
+				"  line 2: - \"x\"\n  line 2: + \"b\""},
+		{`assert_eq(range(20), range(1, 21))`,
+			"assert_eq failed\n" +
+				"  actual:   [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19]\n" +
+				"  expected: [1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20]\n" +
+				"  at [0]: actual 0, expected 1\n  at [1]: actual 1, expected 2\n  at [2]: actual 2, expected 3\n" +
+				"  at [3]: actual 3, expected 4\n  at [4]: actual 4, expected 5\n  at [5]: actual 5, expected 6\n" +
+				"  at [6]: actual 6, expected 7\n  at [7]: actual 7, expected 8\n  at [8]: actual 8, expected 9\n" +
+				"  at [9]: actual 9, expected 10\n  ... and 10 more differences"},
+		{`assert_error(fn() { 1 })`, "assert_error failed\n  expected an error, got: 1"},
+		{`assert_error(fn() { 1 + true }, "undefined")`,
+			"assert_error failed\n  error:    \"type mismatch: INTEGER + BOOLEAN\"\n  expected: error containing \"undefined\""},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message for %q.\nexpected=%q\ngot=     %q", tt.input, tt.expected, errObj.Message)
+		}
+		if !errors.Is(errObj, ErrAssertion) {
+			t.Errorf("error for %q is not ErrAssertion", tt.input)
+		}
+	}
+}
+
+func TestAssertArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`assert()`, "wrong number of arguments. got=0, want=1 or 2"},
+		{`assert_eq(1)`, "wrong number of arguments. got=1, want=2 or 3"},
+		{`assert_error(1)`, "first argument to `assert_error` must be FUNCTION, got INTEGER"},
+		{`assert_error(fn() { 1 + true }, 1)`, "second argument to `assert_error` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("no error for %q", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected || errors.Is(errObj, ErrAssertion) {
+			t.Errorf("wrong error for %q. expected=%q, got=%q (cause %v)", tt.input, tt.expected, errObj.Message, errObj.Cause)
+		}
+	}
+}
+
+func TestAssertErrorLimits(t *testing.T) {
+	// 실행 제한에 걸린 에러는 assert_error 가 잡지 않는다
+	input := `let loop = fn() { loop() }; assert_error(fn() { loop() })`
+	evaluated := testEvalContext(context.Background(), input, Options{MaxDepth: 100})
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || !errors.Is(errObj, ErrMaxDepth) {
+		t.Errorf("expected max depth error. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestAssertErrorHookStop(t *testing.T) {
+	// Hook 이 멈춘 평가도 assert_error 가 잡지 않는다
+	input := `assert_error(fn() {
+  1
+})`
+	hook := &recordingHook{stopAt: 2}
+	evaluated := testEvalContext(context.Background(), input, Options{Hook: hook})
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || !errors.Is(errObj, ErrHookStopped) || !errors.Is(errObj, errStopped) {
+		t.Errorf("expected hook error. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestAssertErrorCatchesCause(t *testing.T) {
+	// Go 함수가 반환한 에러처럼 Cause 가 있더라도 평가를 멈춘 에러가 아니라면 assert_error 가 잡는다
+	env := object.NewEnvironment()
+	env.Set("fail", &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		return &object.Error{Message: "file not found", Cause: os.ErrNotExist}
+	}})
+	p := parser.New(lexer.New(`assert_error(fail, "not found")`))
+	evaluated := Eval(p.ParseProgram(), env)
+	if evaluated.Inspect() != "file not found" {
+		t.Errorf("wrong result. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestAssertEqLongLines(t *testing.T) {
+	// 줄이 너무 많으면 최장 공통 부분 수열을 구하지 않고 처음으로 다른 줄만 보여준다
+	input := "let lines = repeat(\"a\n\", 1100); assert_eq(lines + \"b\", lines + \"c\")"
+	evaluated := testEval(input)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+	}
+	expected := "\n  line 1101: - \"c\"\n  line 1101: + \"b\"\n  (only the first differing line is shown)"
+	if !strings.HasSuffix(errObj.Message, expected) {
+		t.Errorf("wrong differences. got=%q", errObj.Message[strings.LastIndex(errObj.Message, "\"\n")+1:])
+	}
+}
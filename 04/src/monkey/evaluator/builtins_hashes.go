@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"monkey/object"
+)
+
+// 해시를 다루는 내장함수들. 인자로 받은 해시는 바꾸지 않고 항상 새로운 객체를 반환한다.
+// keys, values, items 는 키가 해시에 추가된 순서대로 원소를 반환한다.
+
+// keysBuiltin 은 keys(h) 로 해시의 키들을 배열로 반환한다
+func keysBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("keys", args, 1)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Key
+	}
+	return &object.Array{Elements: elements}
+}
+
+// valuesBuiltin 은 values(h) 로 해시의 값들을 키의 순서대로 배열로 반환한다
+func valuesBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("values", args, 1)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Value
+	}
+	return &object.Array{Elements: elements}
+}
+
+// itemsBuiltin 은 items(h) 로 해시의 키-값 쌍들을 [key, value] 배열들의 배열로 반환한다
+func itemsBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("items", args, 1)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.Pairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// hasBuiltin 은 has(h, key) 로 해시에 key 가 있는지 반환한다
+func hasBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("has", args, 2)
+	if err != nil {
+		return err
+	}
+	key, ok := object.AsHashable(args[1])
+	if !ok {
+		return newError("unusable as hash key: %s", args[1].Type())
+	}
+
+	_, ok = hash.Get(key)
+	return nativeBoolToBooleanObject(ok)
+}
+
+// deleteBuiltin 은 delete(h, key) 로 key 를 제외한 새로운 해시를 반환한다. key 가 없다면 h 와 같은 내용의 해시를 반환한다
+func deleteBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("delete", args, 2)
+	if err != nil {
+		return err
+	}
+	key, ok := object.AsHashable(args[1])
+	if !ok {
+		return newError("unusable as hash key: %s", args[1].Type())
+	}
+
+	result := copyHash(hash)
+	result.Delete(key)
+	return result
+}
+
+// mergeBuiltin 은 merge(a, b, ...) 로 해시들을 합친 새로운 해시를 반환한다.
+// 같은 키가 있다면 처음 나온 위치에 뒤의 해시의 값을 사용한다
+func mergeBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want at least 1",
+			len(args))
+	}
+
+	result := object.NewHash()
+	for _, arg := range args {
+		hash, ok := arg.(*object.Hash)
+		if !ok {
+			return newError("argument to `merge` must be HASH, got %s",
+				arg.Type())
+		}
+		for _, pair := range hash.Pairs() {
+			result.Set(pair.Key.(object.Hashable), pair.Value)
+		}
+	}
+	return result
+}
+
+// hashArgument 는 name 내장함수가 n 개의 인자를 받았고 첫 번째 인자가 해시인지 확인한다
+func hashArgument(name string, args []object.Object, n int) (*object.Hash, *object.Error) {
+	if len(args) != n {
+		return nil, newError("wrong number of arguments. got=%d, want=%d",
+			len(args), n)
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return nil, newError("argument to `%s` must be HASH, got %s",
+			name, args[0].Type())
+	}
+	return hash, nil
+}
+
+// copyHash 는 hash 와 같은 키-값 쌍을 같은 순서로 가진 새로운 해시를 만든다
+func copyHash(hash *object.Hash) *object.Hash {
+	result := object.NewHash()
+	for _, pair := range hash.Pairs() {
+		result.Set(pair.Key.(object.Hashable), pair.Value)
+	}
+	return result
+}
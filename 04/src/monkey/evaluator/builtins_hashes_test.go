@@ -0,0 +1,84 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestHashBuiltins(t *testing.T) {
+	// 결과를 Inspect 한 문자열로 비교한다. keys, values, items 는 키가 추가된 순서를 따른다
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({"b": 2, "a": 1, "c": 3})`, "[b, a, c]"},
+		{`keys({3: "c", 1: "a", -2: "b"})`, "[3, 1, -2]"},
+		{`keys({"one": 1, true: 2, 10: 3, false: 4})`, "[one, true, 10, false]"},
+		{`keys({})`, "[]"},
+		{`values({"b": 2, "a": 1, "c": 3})`, "[2, 1, 3]"},
+		{`items({"b": 2, "a": 1})`, "[[b, 2], [a, 1]]"},
+		{`keys({"a": 1, "b": 2, "a": 3})`, "[a, b]"},
+		{`values({"a": 1, "b": 2, "a": 3})`, "[3, 2]"},
+		{`keys(merge({"b": 1}, {"a": 2, "b": 3}))`, "[b, a]"},
+		{`keys(delete({"a": 1, "b": 2, "c": 3}, "b"))`, "[a, c]"},
+		{`{"c": 1, "a": [1, 2], "b": {"x": true}}`, "{c: 1, a: [1, 2], b: {x: true}}"},
+		{`has({"a": 1}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`has({1: 1}, 1)`, "true"},
+		{`keys(delete({"a": 1, "b": 2}, "a"))`, "[b]"},
+		{`keys(delete({"a": 1, "b": 2}, "z"))`, "[a, b]"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a"); keys(h)`, "[a, b]"},
+		{`items(merge({"a": 1, "b": 2}, {"b": 3, "c": 4}))`, "[[a, 1], [b, 3], [c, 4]]"},
+		{`items(merge({"a": 1}, {"a": 2}, {"a": 3}))`, "[[a, 3]]"},
+		{`let h = {"a": 1}; merge(h, {"b": 2}); keys(h)`, "[a]"},
+		{`len({"a": 1, "b": 2})`, "2"},
+		{`len({})`, "0"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if isError(evaluated) {
+			t.Errorf("unexpected error for %q: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`keys([1])`, "argument to `keys` must be HASH, got ARRAY"},
+		{`keys({}, {})`, "wrong number of arguments. got=2, want=1"},
+		{`values(1)`, "argument to `values` must be HASH, got INTEGER"},
+		{`items("a")`, "argument to `items` must be HASH, got STRING"},
+		{`has({})`, "wrong number of arguments. got=1, want=2"},
+		{`has({}, fn(x) { x })`, "unusable as hash key: FUNCTION"},
+		{`delete([], 1)`, "argument to `delete` must be HASH, got ARRAY"},
+		{`delete({}, [fn(x) { x }])`, "unusable as hash key: ARRAY"},
+		{`merge()`, "wrong number of arguments. got=0, want at least 1"},
+		{`merge({}, 1)`, "argument to `merge` must be HASH, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)",
+				tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package evaluator
+
+import (
+	"fmt"
+	"monkey/object"
+	"strings"
+)
+
+// 문자열을 다루는 내장함수들. 인덱스와 길이는 len 과 같이 바이트 단위로 센다.
+
+// splitBuiltin 은 split(s, sep) 로 s 를 sep 을 기준으로 나눈 문자열 배열을 반환한다.
+// sep 이 빈 문자열이라면 문자 단위로 나눈다
+func splitBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("split", args, 2)
+	if err != nil {
+		return err
+	}
+	return stringArray(strings.Split(strs[0], strs[1]))
+}
+
+// joinBuiltin 은 join(arr, sep) 로 문자열 배열의 원소들을 sep 으로 이어 붙인다
+func joinBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `join` must be ARRAY, got %s",
+			args[0].Type())
+	}
+	sep, ok := args[1].(*object.String)
+	if !ok {
+		return newError("argument to `join` must be STRING, got %s",
+			args[1].Type())
+	}
+
+	// 결과의 길이는 원소들의 길이와 구분자들의 길이의 합이다
+	elements := make([]string, len(arr.Elements))
+	size := 0
+	for i, e := range arr.Elements {
+		str, ok := e.(*object.String)
+		if !ok {
+			return newError("element %d of `join` argument must be STRING, got %s",
+				i, e.Type())
+		}
+		elements[i] = str.Value
+		size += len(str.Value)
+	}
+	if n := len(elements) - 1; n > 0 && len(sep.Value) > 0 {
+		if n > (maxInt-size)/len(sep.Value) {
+			return newError("result of `join` too large")
+		}
+		size += n * len(sep.Value)
+	}
+	if err := reserveString(applier, size); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Join(elements, sep.Value)}
+}
+
+// trimBuiltin 은 trim(s) 로 앞뒤의 공백을, trim(s, cutset) 으로 앞뒤의 cutset 에 속한 문자들을 제거한다
+func trimBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	if len(args) == 1 {
+		strs, err := stringArguments("trim", args, 1)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: strings.TrimSpace(strs[0])}
+	}
+
+	strs, err := stringArguments("trim", args, 2)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Trim(strs[0], strs[1])}
+}
+
+// containsBuiltin 은 contains(s, sub) 로 s 가 sub 를 포함하는지 반환한다.
+// contains(arr, value) 처럼 배열을 넘기면 value 와 같은 원소가 있는지 반환한다
+func containsBuiltin(args ...object.Object) object.Object {
+	if arr, ok := arrayArgument(args); ok {
+		return nativeBoolToBooleanObject(arrayIndexOf(arr, args[1]) >= 0)
+	}
+
+	strs, err := stringArguments("contains", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.Contains(strs[0], strs[1]))
+}
+
+// indexOfBuiltin 은 index_of(s, sub) 로 s 에서 sub 가 처음 나타나는 위치를 반환한다. 없다면 -1 을 반환한다.
+// index_of(arr, value) 처럼 배열을 넘기면 value 와 같은 원소의 위치를 반환한다
+func indexOfBuiltin(args ...object.Object) object.Object {
+	if arr, ok := arrayArgument(args); ok {
+		return &object.Integer{Value: int64(arrayIndexOf(arr, args[1]))}
+	}
+
+	strs, err := stringArguments("index_of", args, 2)
+	if err != nil {
+		return err
+	}
+	return &object.Integer{Value: int64(strings.Index(strs[0], strs[1]))}
+}
+
+// replaceBuiltin 은 replace(s, old, new) 로 s 의 모든 old 를 new 로 바꾼다
+// replaceBuiltin 은 replace(s, old, new) 로 s 의 모든 old 를 new 로 바꾼다.
+// 결과의 길이를 미리 계산하여 문자열을 할당하기 전에 MaxCollectionSize 를 확인한다
+func replaceBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	strs, err := stringArguments("replace", args, 3)
+	if err != nil {
+		return err
+	}
+	// old 가 빈 문자열이면 strings.Count 는 문자 사이의 위치 수를 반환하고, ReplaceAll 은 그 위치마다 new 를 넣는다
+	size := len(strs[0])
+	if growth := len(strs[2]) - len(strs[1]); growth != 0 {
+		n := strings.Count(strs[0], strs[1])
+		if growth > 0 && n > (maxInt-size)/growth {
+			return newError("result of `replace` too large")
+		}
+		size += n * growth
+	}
+	if err := reserveString(applier, size); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ReplaceAll(strs[0], strs[1], strs[2])}
+}
+
+func upperBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("upper", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToUpper(strs[0])}
+}
+
+func lowerBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("lower", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToLower(strs[0])}
+}
+
+func startsWithBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("starts_with", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasPrefix(strs[0], strs[1]))
+}
+
+func endsWithBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("ends_with", args, 2)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasSuffix(strs[0], strs[1]))
+}
+
+// repeatBuiltin 은 repeat(s, n) 으로 s 를 n 번 반복한 문자열을 반환한다.
+// 결과의 길이를 미리 계산하여 문자열을 할당하기 전에 MaxCollectionSize 를 확인한다
+func repeatBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `repeat` must be STRING, got %s",
+			args[0].Type())
+	}
+	count, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("argument to `repeat` must be INTEGER, got %s",
+			args[1].Type())
+	}
+	if count.Value < 0 {
+		return newError("repeat count must not be negative, got %d", count.Value)
+	}
+	// 결과의 길이가 int 범위를 넘으면 strings.Repeat 가 panic 하므로 미리 확인한다
+	if len(str.Value) > 0 && count.Value > int64(maxInt/len(str.Value)) {
+		return newError("repeat count too large, got %d", count.Value)
+	}
+	if err := reserveString(applier, len(str.Value)*int(count.Value)); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Repeat(str.Value, int(count.Value))}
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// reserveString 은 길이가 size 인 문자열을 할당하기 전에 MaxCollectionSize 를 넘는지 확인한다
+func reserveString(applier object.Applier, size int) *object.Error {
+	if s, ok := applier.(*state); ok {
+		return s.reserve(object.STRING_OBJ, int64(size))
+	}
+	return nil
+}
+
+// formatBuiltin 은 format(f, args...) 로 Go 의 fmt.Sprintf 와 같은 형식의 문자열을 만든다.
+// 정수, 문자열, 불리언은 Go 의 값으로 바꾸어 전달하고, 그 밖의 객체는 Inspect 한 문자열을 전달한다
+func formatBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want at least 1",
+			len(args))
+	}
+	f, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `format` must be STRING, got %s",
+			args[0].Type())
+	}
+
+	values := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		switch arg := arg.(type) {
+		case *object.Integer:
+			values[i] = arg.Value
+		case *object.String:
+			values[i] = arg.Value
+		case *object.Boolean:
+			values[i] = arg.Value
+		default:
+			values[i] = arg.Inspect()
+		}
+	}
+	return &object.String{Value: fmt.Sprintf(f.Value, values...)}
+}
+
+// charsBuiltin 은 chars(s) 로 s 를 유니코드 문자 하나씩으로 나눈 문자열 배열을 반환한다
+func charsBuiltin(args ...object.Object) object.Object {
+	strs, err := stringArguments("chars", args, 1)
+	if err != nil {
+		return err
+	}
+
+	chars := make([]string, 0, len(strs[0]))
+	for _, r := range strs[0] {
+		chars = append(chars, string(r))
+	}
+	return stringArray(chars)
+}
+
+// stringArguments 는 name 내장함수가 n 개의 문자열 인자를 받았는지 확인하고 그 값들을 반환한다
+func stringArguments(name string, args []object.Object, n int) ([]string, *object.Error) {
+	if len(args) != n {
+		return nil, newError("wrong number of arguments. got=%d, want=%d",
+			len(args), n)
+	}
+
+	strs := make([]string, n)
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newError("argument to `%s` must be STRING, got %s",
+				name, arg.Type())
+		}
+		strs[i] = str.Value
+	}
+	return strs, nil
+}
+
+// arrayArgument 는 두 개의 인자 중 첫 번째가 배열이라면 그 배열을 반환한다
+func arrayArgument(args []object.Object) (*object.Array, bool) {
+	if len(args) != 2 {
+		return nil, false
+	}
+	arr, ok := args[0].(*object.Array)
+	return arr, ok
+}
+
+func stringArray(strs []string) *object.Array {
+	elements := make([]object.Object, len(strs))
+	for i, s := range strs {
+		elements[i] = &object.String{Value: s}
+	}
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`split("a,b,c", ",")`, []string{"a", "b", "c"}},
+		{`split("abc", "")`, []string{"a", "b", "c"}},
+		{`split("abc", ",")`, []string{"abc"}},
+		{`join(["a", "b", "c"], ", ")`, "a, b, c"},
+		{`join([], ", ")`, ""},
+		{`trim("  hello  ")`, "hello"},
+		{`trim("--hello--", "-")`, "hello"},
+		{`contains("hello world", "o w")`, true},
+		{`contains("hello world", "xyz")`, false},
+		{`index_of("hello", "l")`, 2},
+		{`index_of("hello", "z")`, -1},
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`upper("Monkey")`, "MONKEY"},
+		{`lower("Monkey")`, "monkey"},
+		{`starts_with("monkey", "mon")`, true},
+		{`starts_with("monkey", "key")`, false},
+		{`ends_with("monkey", "key")`, true},
+		{`ends_with("monkey", "mon")`, false},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`format("%s is %d years old", "Monkey", 3)`, "Monkey is 3 years old"},
+		{`format("%5d|%-5s|%t", 42, "ab", true)`, "   42|ab   |true"},
+		{`format("%v and %v", [1, 2], if (false) { 1 })`, "[1, 2] and null"},
+		{`format("100%%")`, "100%"},
+		{`chars("héllo")`, []string{"h", "é", "l", "l", "o"}},
+		{`chars("")`, []string{}},
+		{`join(split("a b c", " "), "-")`, "a-b-c"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testStringObject(t, evaluated, expected)
+		case []string:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d",
+					len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testStringObject(t, array.Elements[i], expectedElem)
+			}
+		}
+	}
+}
+
+func TestStringBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`split("a")`, "wrong number of arguments. got=1, want=2"},
+		{`split(1, ",")`, "argument to `split` must be STRING, got INTEGER"},
+		{`join("abc", ",")`, "argument to `join` must be ARRAY, got STRING"},
+		{`join(["a"], 1)`, "argument to `join` must be STRING, got INTEGER"},
+		{`join(["a", 1], ",")`, "element 1 of `join` argument must be STRING, got INTEGER"},
+		{`trim()`, "wrong number of arguments. got=0, want=1 or 2"},
+		{`trim(1)`, "argument to `trim` must be STRING, got INTEGER"},
+		{`contains("a", 1)`, "argument to `contains` must be STRING, got INTEGER"},
+		{`index_of(1, "a")`, "argument to `index_of` must be STRING, got INTEGER"},
+		{`replace("a", "b")`, "wrong number of arguments. got=2, want=3"},
+		{`upper(true)`, "argument to `upper` must be STRING, got BOOLEAN"},
+		{`lower([])`, "argument to `lower` must be STRING, got ARRAY"},
+		{`starts_with("a")`, "wrong number of arguments. got=1, want=2"},
+		{`ends_with("a", 1)`, "argument to `ends_with` must be STRING, got INTEGER"},
+		{`repeat("a", "b")`, "argument to `repeat` must be INTEGER, got STRING"},
+		{`repeat("a", -1)`, "repeat count must not be negative, got -1"},
+		{`repeat("ab", 9223372036854775807)`, "repeat count too large, got 9223372036854775807"},
+		{`format()`, "wrong number of arguments. got=0, want at least 1"},
+		{`format(1)`, "argument to `format` must be STRING, got INTEGER"},
+		{`chars(1)`, "argument to `chars` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)",
+				tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func testStringObject(t *testing.T, obj object.Object, expected string) bool {
+	result, ok := obj.(*object.String)
+	if !ok {
+		t.Errorf("object is not String. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%q, want=%q",
+			result.Value, expected)
+		return false
+	}
+
+	return true
+}
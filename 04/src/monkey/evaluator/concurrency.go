@@ -0,0 +1,240 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/object"
+)
+
+// 동시성을 위한 내장함수들. 함수 호출은 Go 의 고루틴에서, 채널은 Go 의 채널로 실행된다.
+// 기다리는 연산(await, send, recv, select)은 EvalContext 의 컨텍스트가 취소되면 에러를 반환한다.
+// 컨텍스트가 없는데 모든 고루틴이 기다리고 있어 누구도 연산을 끝내줄 수 없다면 "deadlock: " 으로 시작하는 에러를 반환한다.
+
+// spawnBuiltin 은 spawn(fn, args...) 로 fn 을 새로운 고루틴에서 호출하고 TASK 를 반환한다
+func spawnBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want at least 1",
+			len(args))
+	}
+	if !isFunction(args[0]) {
+		return newError("argument to `spawn` must be FUNCTION, got %s",
+			args[0].Type())
+	}
+
+	fn := args[0]
+	fnArgs := append([]object.Object{}, args[1:]...)
+
+	// 새로운 고루틴은 자신만의 호출 깊이를 갖되 실행 제한은 부모와 공유한다.
+	// cancel 로 취소할 수 있도록 부모의 컨텍스트에서 파생한 컨텍스트를 사용하므로, 부모가 취소되거나
+	// EvalContext 가 돌아오면 함께 취소되고 이 고루틴에서 spawn 한 TASK 들도 함께 취소된다
+	var child object.Applier = applier
+	var sched *object.Scheduler
+	var cancel context.CancelFunc
+	if s, ok := applier.(*state); ok {
+		cs := s.fork()
+		cs.ctx, cancel = context.WithCancel(s.ctx)
+		cs.limited = true
+		sched = s.sched
+		if cs.hook != nil {
+			cs.setHook(forkHook(cs.hook))
+		}
+		child = cs
+	}
+
+	return object.NewTask(sched, func() object.Object {
+		return child.Apply(fn, fnArgs...)
+	}, cancel)
+}
+
+// awaitBuiltin 은 await(task) 로 TASK 가 끝날 때까지 기다려 그 결과를 반환한다
+func awaitBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	task, ok := args[0].(*object.Task)
+	if !ok {
+		return newError("argument to `await` must be TASK, got %s",
+			args[0].Type())
+	}
+
+	result, err := task.Await(waitOf(applier))
+	if err != nil {
+		return waitError(applier, err, "await on task that never finishes")
+	}
+	return result
+}
+
+// cancelBuiltin 은 cancel(task) 로 TASK 의 실행을 취소한다. TASK 와 그 TASK 가 spawn 한 TASK 들은
+// 다음 단계나 기다리던 연산에서 멈추고 취소 에러로 끝난다. 이미 끝난 TASK 라면 아무 일도 하지 않는다
+func cancelBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	task, ok := args[0].(*object.Task)
+	if !ok {
+		return newError("argument to `cancel` must be TASK, got %s",
+			args[0].Type())
+	}
+
+	task.Cancel()
+	return NULL
+}
+
+// channelBuiltin 은 channel() 또는 channel(cap) 으로 버퍼 크기가 cap 인 CHANNEL 을 만든다
+func channelBuiltin(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("wrong number of arguments. got=%d, want=0 or 1",
+			len(args))
+	}
+	if len(args) == 0 {
+		return object.NewChannel(0)
+	}
+
+	capacity, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `channel` must be INTEGER, got %s",
+			args[0].Type())
+	}
+	if capacity.Value < 0 {
+		return newError("channel capacity must not be negative, got %d",
+			capacity.Value)
+	}
+	return object.NewChannel(int(capacity.Value))
+}
+
+// sendBuiltin 은 send(ch, value) 로 채널에 값을 보낸다
+func sendBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	ch, ok := args[0].(*object.Channel)
+	if !ok {
+		return newError("argument to `send` must be CHANNEL, got %s",
+			args[0].Type())
+	}
+
+	if err := ch.Send(args[1], waitOf(applier)); err != nil {
+		return waitError(applier, err, "send on channel with no receiver")
+	}
+	return NULL
+}
+
+// recvBuiltin 은 recv(ch) 로 채널에서 값을 받는다. 닫힌 채널에서 더 받을 값이 없다면 NULL 을 반환한다
+func recvBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	ch, ok := args[0].(*object.Channel)
+	if !ok {
+		return newError("argument to `recv` must be CHANNEL, got %s",
+			args[0].Type())
+	}
+
+	v, ok, err := ch.Recv(waitOf(applier))
+	if err != nil {
+		return waitError(applier, err, "recv on channel with no sender")
+	}
+	if !ok {
+		return NULL
+	}
+	return v
+}
+
+// closeBuiltin 은 close(ch) 로 채널을 닫는다
+func closeBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	ch, ok := args[0].(*object.Channel)
+	if !ok {
+		return newError("argument to `close` must be CHANNEL, got %s",
+			args[0].Type())
+	}
+
+	if err := ch.Close(); err != nil {
+		return newError("close of closed channel")
+	}
+	return NULL
+}
+
+// selectBuiltin 은 select(cases) 또는 select(cases, true) 로 여러 채널 연산 중 먼저 준비된 하나를 실행한다.
+// cases 의 원소가 CHANNEL 이면 값을 받고, [CHANNEL, value] 배열이면 value 를 보낸다.
+// 실행된 연산의 인덱스와 받은 값을 [index, value] 로 반환한다. 보내기 연산이거나 채널이 닫혀 있다면 value 는 NULL 이다.
+// 두 번째 인자가 true 라면 준비된 연산이 없을 때 기다리지 않고 [-1, null] 을 반환한다.
+func selectBuiltin(applier object.Applier, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	cases, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `select` must be ARRAY, got %s",
+			args[0].Type())
+	}
+	nonBlocking := len(args) == 2 && isTruthy(args[1])
+
+	selectCases := make([]object.SelectCase, 0, len(cases.Elements))
+	for i, c := range cases.Elements {
+		switch c := c.(type) {
+		case *object.Channel:
+			selectCases = append(selectCases, object.SelectCase{Chan: c})
+		case *object.Array:
+			if len(c.Elements) != 2 || c.Elements[0].Type() != object.CHANNEL_OBJ {
+				return newError("select case %d must be CHANNEL or [CHANNEL, value]", i)
+			}
+			selectCases = append(selectCases, object.SelectCase{
+				Chan:  c.Elements[0].(*object.Channel),
+				Send:  true,
+				Value: c.Elements[1],
+			})
+		default:
+			return newError("select case %d must be CHANNEL or [CHANNEL, value], got %s",
+				i, c.Type())
+		}
+	}
+
+	chosen, v, ok, err := object.Select(selectCases, !nonBlocking, waitOf(applier))
+	if err != nil {
+		return waitError(applier, err, "select with no ready case")
+	}
+
+	value := object.Object(NULL)
+	if ok {
+		value = v
+	}
+	return &object.Array{Elements: []object.Object{&object.Integer{Value: int64(chosen)}, value}}
+}
+
+// waitOf 는 applier 가 채널 연산이나 await 로 기다리는 동안의 조건을 반환한다.
+// 호스트가 컨텍스트로 평가를 취소할 수 있다면 교착 상태로 보지 않고 취소될 때까지 기다린다.
+func waitOf(applier object.Applier) object.Wait {
+	s, ok := applier.(*state)
+	if !ok {
+		return object.Wait{}
+	}
+	wait := object.Wait{Cancel: s.done()}
+	if !s.cancelable {
+		wait.Scheduler = s.sched
+	}
+	return wait
+}
+
+// waitError 는 기다리던 연산이 실패한 이유를 에러 객체로 바꾼다. deadlock 은 교착 상태일 때 보여줄 연산의 설명이다.
+func waitError(applier object.Applier, err error, deadlock string) object.Object {
+	switch {
+	case errors.Is(err, object.ErrCanceled):
+		return canceledError(applier)
+	case errors.Is(err, object.ErrDeadlock):
+		return newError("deadlock: %s", deadlock)
+	}
+	return newError("send on closed channel")
+}
+
+func canceledError(applier object.Applier) object.Object {
+	return contextError(applier.(*state).ctx.Err())
+}
@@ -0,0 +1,237 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let t = spawn(fn() { 1 + 2 }); await(t)`, 3},
+		{`let t = spawn(fn(a, b) { a * b }, 6, 7); await(t)`, 42},
+		{`await(spawn(len, "four"))`, 4},
+		{
+			`
+let ch = channel();
+let worker = fn(x) { send(ch, x * 2) };
+spawn(worker, 1);
+spawn(worker, 2);
+recv(ch) + recv(ch)`,
+			6,
+		},
+		{
+			`
+let results = channel(10);
+let fanOut = fn(n) {
+  if (n > 0) {
+    spawn(fn() { send(results, n) });
+    fanOut(n - 1);
+  }
+};
+fanOut(10);
+let sum = fn(n, acc) { if (n == 0) { acc } else { sum(n - 1, acc + recv(results)) } };
+sum(10, 0)`,
+			55,
+		},
+		{
+			`
+let tasks = [spawn(fn() { 10 }), spawn(fn() { 20 })];
+await(tasks[0]) + await(tasks[1])`,
+			30,
+		},
+		{`let ch = channel(1); send(ch, 5); close(ch); recv(ch)`, 5},
+		{`let ch = channel(); close(ch); recv(ch)`, nil},
+		{`let a = channel(1); let b = channel(1); send(b, 5); select([a, b])`, []int{1, 5}},
+		{`let a = channel(1); select([[a, 7]]); recv(a)`, 7},
+		{`select([channel()], true)`, []int{-1}},
+		{`await(spawn(fn() { 1 + true }))`, "type mismatch: INTEGER + BOOLEAN"},
+		{`let ch = channel(); close(ch); send(ch, 1)`, "send on closed channel"},
+		{`let ch = channel(); close(ch); close(ch)`, "close of closed channel"},
+		{`let ch = channel(); close(ch); select([[ch, 1]])`, "send on closed channel"},
+		{`recv(channel())`, "deadlock: recv on channel with no sender"},
+		{`send(channel(), 1)`, "deadlock: send on channel with no receiver"},
+		{`select([channel(), [channel(), 1]])`, "deadlock: select with no ready case"},
+		{`await(spawn(fn() { recv(channel()) }))`, "deadlock: recv on channel with no sender"},
+		{`let ch = channel(); let t = spawn(fn() { send(ch, 1) }); recv(ch) + recv(ch)`,
+			"deadlock: recv on channel with no sender"},
+		{`let t = spawn(fn() { await(t) }); await(t)`, "deadlock: await on task that never finishes"},
+		{`let ch = channel(); let t = spawn(fn() { recv(ch) }); cancel(t); await(t)`, "evaluation cancelled"},
+		{`let t = spawn(fn() { range(1099511627776) }); cancel(t); await(t)`, "evaluation cancelled"},
+		{`let t = spawn(fn() { 1 }); let r = await(t); cancel(t); r`, 1},
+		{`spawn(1)`, "argument to `spawn` must be FUNCTION, got INTEGER"},
+		{`cancel(1)`, "argument to `cancel` must be TASK, got INTEGER"},
+		{`await(1)`, "argument to `await` must be TASK, got INTEGER"},
+		{`channel(-1)`, "channel capacity must not be negative, got -1"},
+		{`recv(1)`, "argument to `recv` must be CHANNEL, got INTEGER"},
+		{`select([1])`, "select case 0 must be CHANNEL or [CHANNEL, value], got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)",
+					evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			testIntegerObject(t, array.Elements[0], int64(expected[0]))
+			if len(expected) == 2 {
+				testIntegerObject(t, array.Elements[1], int64(expected[1]))
+			} else {
+				testNullObject(t, array.Elements[1])
+			}
+		}
+	}
+}
+
+func TestConcurrencyCancel(t *testing.T) {
+	tests := []string{
+		`recv(channel())`,
+		`send(channel(), 1)`,
+		`select([channel()])`,
+		`await(spawn(fn() { recv(channel()) }))`,
+	}
+
+	for _, input := range tests {
+		evaluated := testEvalContext(context.Background(), input,
+			Options{Timeout: 10 * time.Millisecond})
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)",
+				input, evaluated, evaluated)
+			continue
+		}
+		if !errors.Is(errObj, context.DeadlineExceeded) {
+			t.Errorf("wrong error cause for %q. got=%v", input, errObj.Cause)
+		}
+	}
+}
+
+func TestSpawnSharesLimits(t *testing.T) {
+	input := `
+let loop = fn() { loop() };
+let tasks = [spawn(loop), spawn(loop)];
+await(tasks[0]);`
+
+	evaluated := testEvalContext(context.Background(), input,
+		Options{MaxSteps: 1000, MaxDepth: 1000000})
+
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if !errors.Is(errObj, ErrMaxSteps) {
+		t.Errorf("wrong error cause. got=%v", errObj.Cause)
+	}
+}
+
+func TestCancelStopsSpawnedTasks(t *testing.T) {
+	// 취소된 TASK 가 spawn 한 TASK 도 함께 취소된다. 그렇지 않다면 t 는 교착 상태로 끝난다
+	input := `
+let inner = channel();
+let outer = spawn(fn() {
+  let t = spawn(fn() { recv(channel()) });
+  send(inner, t);
+  await(t)
+});
+let t = recv(inner);
+cancel(outer);
+await(t)`
+
+	evaluated := testEval(input)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || !errors.Is(errObj, context.Canceled) {
+		t.Errorf("expected cancellation error. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestCloseRacingSend(t *testing.T) {
+	// close 와 send 가 경쟁해도 send 는 값을 보내거나 에러를 반환할 뿐 panic 하지 않는다
+	input := `
+let ch = channel(1);
+let sender = fn(n) { if (n > 0) { send(ch, n); sender(n - 1) } };
+let tasks = [spawn(sender, 100), spawn(sender, 100)];
+recv(ch);
+close(ch);
+await(tasks[0]);
+await(tasks[1])`
+
+	for i := 0; i < 20; i++ {
+		evaluated := testEval(input)
+		if errObj, ok := evaluated.(*object.Error); ok && errObj.Message != "send on closed channel" {
+			t.Errorf("wrong error from sender. got=%q", errObj.Message)
+		}
+	}
+}
+
+func TestEvalCancelsTasksOnReturn(t *testing.T) {
+	// 평가가 돌아오면 아직 끝나지 않은 TASK 도 취소된다
+	evaluated := testEval(`spawn(fn() { recv(channel()) })`)
+	task, ok := evaluated.(*object.Task)
+	if !ok {
+		t.Fatalf("object is not Task. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("task is still running after the evaluation returned")
+	}
+	errObj, ok := task.Result().(*object.Error)
+	if !ok || !errors.Is(errObj, context.Canceled) {
+		t.Errorf("expected cancellation error. got=%s", task.Result().Inspect())
+	}
+}
+
+func TestDeadlockIsPerEvaluation(t *testing.T) {
+	// 다른 평가가 Go 내장함수 안에서 멈춰 있어도 이 평가의 교착 상태를 찾는다
+	release := make(chan struct{})
+	defer close(release)
+
+	env := object.NewEnvironment()
+	env.Set("wait", &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		<-release
+		return NULL
+	}})
+	p := parser.New(lexer.New(`wait()`))
+	go Eval(p.ParseProgram(), env)
+
+	result := make(chan object.Object, 1)
+	go func() { result <- testEval(`recv(channel())`) }()
+
+	select {
+	case evaluated := <-result:
+		errObj, ok := evaluated.(*object.Error)
+		if !ok || errObj.Message != "deadlock: recv on channel with no sender" {
+			t.Errorf("expected deadlock error. got=%s", evaluated.Inspect())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("deadlock was not detected")
+	}
+}
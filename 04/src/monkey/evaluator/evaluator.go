@@ -1,76 +1,122 @@
 package evaluator
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"monkey/ast"
 	"monkey/object"
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL  = object.NULL
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
 )
 
+// DefaultMaxRecursionDepth 는 함수 호출이 중첩될 수 있는 기본 최대 깊이이다.
+// 이 값을 넘어서면 Go 런타임의 스택이 넘치기 전에 에러 객체를 반환한다. 다른 값은 Options.MaxDepth 로 정한다
+const DefaultMaxRecursionDepth = 10000
+
+// state 는 한 번의 Eval 호출 동안 유지되는 평가 상태를 담는다.
+// 패키지 수준 변수가 아닌 호출마다 새로 만들어지므로 여러 고루틴에서 Eval 을 호출해도 서로 영향을 주지 않는다.
+// spawn 으로 만든 고루틴은 fork 한 state 를 사용한다.
+type state struct {
+	depth    int // 현재 중첩된 함수 호출의 깊이
+	maxDepth int
+
+	// EvalContext 로 설정되는 실행 제한
+	ctx        context.Context
+	cancelable bool              // 호스트가 ctx 로 평가를 취소할 수 있는지 여부. 그렇다면 교착 상태를 확인하지 않는다
+	limited    bool              // 매 단계마다 step 을 호출해야 하는지 여부
+	limits     *limits           // fork 한 state 와 공유한다
+	sched      *object.Scheduler // 이 평가의 교착 상태를 찾는다. fork 한 state 와 공유한다
+
+	// 모듈을 불러오기 위한 상태
+	modules   *ModuleLoader
+	builtins  *object.Environment // 모듈을 평가하는 환경의 바깥 환경. nil 이면 빈 환경에서 평가한다
+	stdout    io.Writer           // puts 가 출력할 곳. nil 이면 os.Stdout
+	file      string              // 평가 중인 파일의 절대 경로. import 의 상대 경로는 이 파일의 디렉터리를 기준으로 한다
+	importing []string            // 평가 중인 모듈들의 경로. 순환 import 를 찾는 데 사용한다
+	importer  *importer           // 모듈을 평가하는 고루틴. 다른 고루틴과의 순환 import 를 찾는 데 사용한다
+
+	hook     Hook       // nil 이면 평가를 관찰하지 않는다
+	branches BranchHook // hook 이 BranchHook 을 구현하면 hook 과 같고, 그렇지 않으면 nil 이다
+}
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	return EvalContext(context.Background(), node, env, Options{})
+}
+
+func (s *state) eval(node ast.Node, env *object.Environment) object.Object {
+	if s.limited {
+		if err := s.step(); err != nil {
+			return err
+		}
+	}
+
 	switch node := node.(type) {
 
 	// Statements
 	case *ast.Program:
-		return evalProgram(node, env)
+		return s.evalProgram(node, env)
 
 	case *ast.BlockStatement:
-		return evalBlockStatement(node, env)
+		return s.evalBlockStatement(node, env)
 
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression, env)
+		return s.eval(node.Expression, env)
 
 	case *ast.ReturnStatement:
-		val := Eval(node.ReturnValue, env)
+		val := s.eval(node.ReturnValue, env)
 		if isError(val) {
 			return val
 		}
 		return &object.ReturnValue{Value: val}
 
 	case *ast.LetStatement:
-		val := Eval(node.Value, env)
+		val := s.eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
+		// 얼린 환경에 바인딩하면 panic 이 발생하므로 에러로 돌려준다
+		if env.Frozen() {
+			return newError("cannot bind %s: environment is frozen", node.Name.Value)
+		}
 		env.Set(node.Name.Value, val)
 
 	// Expressions
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		return s.track(&object.Integer{Value: node.Value})
 
 	case *ast.StringLiteral:
-		return &object.String{Value: node.Value}
+		return s.track(&object.String{Value: node.Value})
 
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 
 	case *ast.PrefixExpression:
-		right := Eval(node.Right, env)
+		right := s.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return s.track(evalPrefixExpression(node.Operator, right))
 
 	case *ast.InfixExpression:
-		left := Eval(node.Left, env)
+		left := s.eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
 
-		right := Eval(node.Right, env)
+		right := s.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		return s.track(evalInfixExpression(node.Operator, left, right))
 
 	case *ast.IfExpression:
-		return evalIfExpression(node, env)
+		return s.evalIfExpression(node, env)
 
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
@@ -78,53 +124,71 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return s.track(&object.Function{
+			Parameters: params,
+			Env:        env,
+			Body:       body,
+			Name:       node.Name,
+			File:       s.file,
+			Pos:        node.Token.Pos,
+		})
 
 	case *ast.CallExpression:
-		function := Eval(node.Function, env)
+		function := s.eval(node.Function, env)
 		if isError(function) {
 			return function
 		}
 
-		args := evalExpressions(node.Arguments, env)
+		args := s.evalExpressions(node.Arguments, env)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		return s.applyFunction(function, args)
 
 		// 배열 리터럴을 평가하는 경우
 	case *ast.ArrayLiteral:
-		elements := evalExpressions(node.Elements, env)
+		elements := s.evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
 			return elements[0]
 		}
-		return &object.Array{Elements: elements}
+		return s.track(&object.Array{Elements: elements})
 
 	case *ast.IndexExpression:
-		left := Eval(node.Left, env) // 왼쪽 대괄호의 왼쪽에 위치한 node 를 평가하여 Object 타입으로 반환
+		left := s.eval(node.Left, env) // 왼쪽 대괄호의 왼쪽에 위치한 node 를 평가하여 Object 타입으로 반환
 		if isError(left) {
 			return left
 		}
-		index := Eval(node.Index, env)
+		index := s.eval(node.Index, env)
 		if isError(index) {
 			return index
 		}
 		return evalIndexExpression(left, index)
 
+	case *ast.SliceExpression:
+		return s.evalSliceExpression(node, env)
+
 	case *ast.HashLiteral: // 해시 리터럴을 평가하는 경우
-		return evalHashLiteral(node, env)
+		return s.evalHashLiteral(node, env)
+
+	case *ast.ImportExpression:
+		return s.evalImportExpression(node)
 
 	}
 
 	return nil
 }
 
-func evalProgram(program *ast.Program, env *object.Environment) object.Object {
+func (s *state) evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, statement := range program.Statements {
-		result = Eval(statement, env)
+		if s.hook != nil {
+			if err := s.hook.Statement(s.file, statement, env); err != nil {
+				return hookError(err)
+			}
+		}
+		result = s.eval(statement, env)
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
@@ -137,14 +201,19 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	return result
 }
 
-func evalBlockStatement(
+func (s *state) evalBlockStatement(
 	block *ast.BlockStatement,
 	env *object.Environment,
 ) object.Object {
 	var result object.Object
 
 	for _, statement := range block.Statements {
-		result = Eval(statement, env)
+		if s.hook != nil {
+			if err := s.hook.Statement(s.file, statement, env); err != nil {
+				return hookError(err)
+			}
+		}
+		result = s.eval(statement, env)
 
 		if result != nil {
 			rt := result.Type()
@@ -184,10 +253,13 @@ func evalInfixExpression(
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
+	case isComparable(left) || isComparable(right):
+		return evalComparableInfixExpression(operator, left, right)
+	// 배열과 해시는 구조적으로, 함수와 같은 그 밖의 객체는 같은 객체인지 비교한다
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equal(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s",
 			left.Type(), operator, right.Type())
@@ -197,6 +269,50 @@ func evalInfixExpression(
 	}
 }
 
+func isComparable(obj object.Object) bool {
+	_, ok := obj.(object.Comparable)
+	return ok
+}
+
+// evalComparableInfixExpression 함수는 호스트가 정의한 Comparable 객체의 비교 연산을 수행합니다.
+func evalComparableInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	var result int
+	var ok bool
+
+	// 왼쪽 피연산자가 Comparable 이 아니라면 오른쪽 기준으로 비교한 뒤 부호를 뒤집는다
+	if l, isLeft := left.(object.Comparable); isLeft {
+		result, ok = l.Compare(right)
+	} else {
+		result, ok = right.(object.Comparable).Compare(left)
+		result = -result
+	}
+
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(ok && result == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(!ok || result != 0)
+	}
+
+	if !ok {
+		return newError("cannot compare: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(result < 0)
+	case ">":
+		return nativeBoolToBooleanObject(result > 0)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
 func evalBangOperatorExpression(right object.Object) object.Object {
 	switch right {
 	case TRUE:
@@ -234,6 +350,9 @@ func evalIntegerInfixExpression(
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal}
 	case "/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
 		return &object.Integer{Value: leftVal / rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
@@ -254,30 +373,41 @@ func evalStringInfixExpression(
 	operator string,
 	left, right object.Object,
 ) object.Object {
-	// 문자열 간의 덧셈 (concatenation) 연산만 지원
-	if operator != "+" {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	// 문자열 간의 덧셈 (concatenation) 과 비교 연산만 지원
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
 		return newError("unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
-
-	leftVal := left.(*object.String).Value
-	rightVal := right.(*object.String).Value
-	return &object.String{Value: leftVal + rightVal}
 }
 
-func evalIfExpression(
+func (s *state) evalIfExpression(
 	ie *ast.IfExpression,
 	env *object.Environment,
 ) object.Object {
-	condition := Eval(ie.Condition, env)
+	condition := s.eval(ie.Condition, env)
 	if isError(condition) {
 		return condition
 	}
 
-	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
+	taken := isTruthy(condition)
+	if s.branches != nil {
+		s.branches.Branch(s.file, ie, taken)
+	}
+
+	if taken {
+		return s.eval(ie.Consequence, env)
 	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
+		return s.eval(ie.Alternative, env)
 	} else {
 		return NULL
 	}
@@ -322,14 +452,14 @@ func isError(obj object.Object) bool {
 	return false
 }
 
-func evalExpressions(
+func (s *state) evalExpressions(
 	exps []ast.Expression,
 	env *object.Environment,
 ) []object.Object {
 	var result []object.Object
 
 	for _, e := range exps {
-		evaluated := Eval(e, env)
+		evaluated := s.eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
@@ -339,19 +469,60 @@ func evalExpressions(
 	return result
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// Apply 는 object.Applier 를 구현하여 내장 함수가 함수 객체를 호출할 수 있게 한다.
+func (s *state) Apply(fn object.Object, args ...object.Object) object.Object {
+	return s.applyFunction(fn, args)
+}
+
+func (s *state) applyFunction(fn object.Object, args []object.Object) object.Object {
+	if s.hook != nil {
+		s.hook.Enter(fn, args)
+		result := s.callFunction(fn, args)
+		s.hook.Exit(fn, result)
+		return result
+	}
+	return s.callFunction(fn, args)
+}
+
+func (s *state) callFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 
 	// 일반 사용자 정의 함수일 때
 	case *object.Function:
+		// 재귀 호출이 너무 깊어지면 Go 스택이 넘쳐 프로세스가 죽기 전에 에러를 반환한다
+		if s.maxDepth > 0 && s.depth >= s.maxDepth {
+			return newLimitError(ErrMaxDepth,
+				"maximum recursion depth %d exceeded", s.maxDepth)
+		}
+
+		// 인자가 모자라면 매개변수에 바인딩할 값이 없다
+		if len(args) < len(fn.Parameters) {
+			return newError("wrong number of arguments. got=%d, want=%d",
+				len(args), len(fn.Parameters))
+		}
+
+		// 함수의 본문은 함수가 정의된 파일에서 평가되는 것으로 본다
+		file := s.file
+		s.depth++
+		s.file = fn.File
 		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
+		evaluated := s.eval(fn.Body, extendedEnv)
+		s.file = file
+		s.depth--
+
 		return unwrapReturnValue(evaluated)
 
 	// 내장 함수일 때
 	case *object.Builtin:
 		// 내장함수일 때에는 ReturnValue 객체를 반환할 일이 없으므로 unwrapReturnValue 함수를 사용하지 않음
-		return fn.Fn(args...)
+		if fn.Callback != nil {
+			return s.track(fn.Callback(s, args...))
+		}
+		return s.track(fn.Fn(args...))
+
+	// 호스트가 정의한 호출 가능한 객체일 때
+	case object.Callable:
+		return s.track(fn.Call(args...))
 
 	default:
 		return newError("not a function: %s", fn.Type())
@@ -389,17 +560,34 @@ func evalIndexExpression(left, index object.Object) object.Object {
 	case left.Type() == object.HASH_OBJ:
 		// 해시에 대한 인덱스의 경우, left node 는 ast.HashLiteral 이어야 하며, index 는 hashable 해야 한다.
 		return evalHashIndexExpression(left, index)
+	case isIndexable(left):
+		// 호스트가 정의한 객체는 인덱스 연산을 직접 처리한다
+		result := left.(object.Indexable).Index(index)
+		if result == nil {
+			return NULL
+		}
+		return result
 	default:
 		return newError("index operator not supported: %s", left.Type())
 	}
 }
 
+func isIndexable(obj object.Object) bool {
+	_, ok := obj.(object.Indexable)
+	return ok
+}
+
 // evalArrayIndexExpression 함수는 배열에 대한 인덱스 연산을 수행합니다.
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
 	idx := index.(*object.Integer).Value
 	max := int64(len(arrayObject.Elements) - 1)
 
+	// 음수 인덱스는 배열의 끝에서부터 센다. (-1 은 마지막 원소)
+	if idx < 0 {
+		idx += max + 1
+	}
+
 	// 인덱스가 배열의 범위를 벗어나는 경우 NULL을 반환
 	if idx < 0 || idx > max {
 		return NULL
@@ -409,38 +597,37 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 }
 
 // evalHashLiteral 함수는 해시 리터럴을 평가함
-func evalHashLiteral(
+func (s *state) evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
 ) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	// hash literal 의 각 키-값 쌍을 평가
-	for keyNode, valueNode := range node.Pairs {
+	// hash literal 의 각 키-값 쌍을 소스 코드에 나온 순서대로 평가
+	for _, pairNode := range node.Pairs {
 		// 우선 key node 를 평가한다.
-		key := Eval(keyNode, env)
+		key := s.eval(pairNode.Key, env)
 		if isError(key) {
 			return key
 		}
 
 		// key node 가 hashable 한지 확인한다. 그렇지 않다면 에러를 반환한다.
-		hashKey, ok := key.(object.Hashable)
+		hashKey, ok := object.AsHashable(key)
 		if !ok {
 			return newError("unusable as hash key: %s", key.Type())
 		}
 
 		// value node 를 평가한다.
-		value := Eval(valueNode, env)
+		value := s.eval(pairNode.Value, env)
 		if isError(value) {
 			return value
 		}
 
-		// key node 를 사용해 HashKey 를 생성하고, 이를 사용해 HashPair 를 생성한다.
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		// 같은 키가 여러 번 나오면 처음 나온 위치에 마지막 값이 저장된다.
+		hash.Set(hashKey, value)
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return s.track(hash)
 }
 
 // evalHashIndexExpression 함수는 해시에 대한 인덱스 연산을 수행함
@@ -448,16 +635,16 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
 
 	// 인덱스로 사용될 값은 hashaable 해야 한다.
-	key, ok := index.(object.Hashable)
+	key, ok := object.AsHashable(index)
 	if !ok {
 		return newError("unusable as hash key: %s", index.Type())
 	}
 
-	// hash Object 의 Pairs 맵에서 key 에 해당하는 value 를 찾아 반환한다.
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	// hash Object 에서 key 에 해당하는 value 를 찾아 반환한다.
+	value, ok := hashObject.Get(key)
 	if !ok {
 		return NULL
 	}
 
-	return pair.Value
+	return value
 }
@@ -1,9 +1,12 @@
 package evaluator
 
 import (
+	"context"
+	"fmt"
 	"monkey/lexer"
 	"monkey/object"
 	"monkey/parser"
+	"sync"
 	"testing"
 )
 
@@ -67,6 +70,82 @@ func TestEvalBooleanExpression(t *testing.T) {
 	}
 }
 
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"monkey" == "monkey"`, true},
+		{`"monkey" == "donkey"`, false},
+		{`"monkey" != "donkey"`, true},
+		{`[1, 2] == [1, 2]`, true},
+		{`[1, 2] != [1, 2]`, false},
+		{`[1, 2] == [2, 1]`, false},
+		{`[1, 2] == [1, 2, 3]`, false},
+		{`[] == []`, true},
+		{`[[1, "a"], [true]] == [[1, "a"], [true]]`, true},
+		{`[[1, "a"], [true]] == [[1, "a"], [false]]`, false},
+		{`{"a": 1, "b": [2]} == {"a": 1, "b": [2]}`, true},
+		{`{"a": 1, "b": 2} == {"b": 2, "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"a": 1, "b": 2}`, false},
+		{`{} == {}`, true},
+		{`[1] == 1`, false},
+		{`[1] == {1: 1}`, false},
+		{`1 == "1"`, false},
+		// 함수는 같은 객체일 때에만 같다
+		{`let f = fn(x) { x }; f == f`, true},
+		{`fn(x) { x } == fn(x) { x }`, false},
+		{`let f = fn(x) { x }; [f, 1] == [f, 1]`, true},
+		{`len == len`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestArraysAsHashKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{[1, 2]: 3}[[1, 2]]`, 3},
+		{`{[1, 2]: 3}[[2, 1]]`, nil},
+		{`{[]: 1}[[]]`, 1},
+		{`{["a", [true]]: 5}[["a", [true]]]`, 5},
+		{`let grid = {[0, 0]: 1, [0, 1]: 2}; grid[[0, 1]]`, 2},
+		{`len({[1, 2]: 1, [1, 2]: 2})`, 1},
+		{`has({[1, "a"]: 1}, [1, "a"])`, true},
+		{`{[1, fn(x) { x }]: 1}`, "unusable as hash key: ARRAY"},
+		{`{[1]: 1}[[{}]]`, "unusable as hash key: ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
 func TestBangOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -169,6 +248,10 @@ func TestErrorHandling(t *testing.T) {
 			"5 + true;",
 			"type mismatch: INTEGER + BOOLEAN",
 		},
+		{
+			"10 / (5 - 5)",
+			"division by zero",
+		},
 		{
 			"5 + true; 5;",
 			"type mismatch: INTEGER + BOOLEAN",
@@ -274,7 +357,7 @@ func TestFunctionObject(t *testing.T) {
 		t.Fatalf("parameter is not 'x'. got=%q", fn.Parameters[0])
 	}
 
-	expectedBody := "(x + 2)"
+	expectedBody := "{ (x + 2) }"
 
 	if fn.Body.String() != expectedBody {
 		t.Fatalf("body is not %q. got=%q", expectedBody, fn.Body.String())
@@ -482,6 +565,14 @@ func TestArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -514,26 +605,39 @@ func TestHashLiterals(t *testing.T) {
 		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		TRUE.HashKey():                             5,
-		FALSE.HashKey():                            6,
+	// 해시의 키-값 쌍은 소스 코드에 나온 순서대로 저장된다
+	expected := []struct {
+		key   object.Hashable
+		value int64
+	}{
+		{&object.String{Value: "one"}, 1},
+		{&object.String{Value: "two"}, 2},
+		{&object.String{Value: "three"}, 3},
+		{&object.Integer{Value: 4}, 4},
+		{TRUE, 5},
+		{FALSE, 6},
 	}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
 
-	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+	for i, pair := range result.Pairs() {
+		if pair.Key.(object.Hashable).HashKey() != expected[i].key.HashKey() {
+			t.Errorf("pair %d has wrong key. expected=%s, got=%s",
+				i, expected[i].key.Inspect(), pair.Key.Inspect())
+		}
+
+		value, ok := result.Get(expected[i].key)
 		if !ok {
 			t.Errorf("no pair for given key in Pairs")
 		}
 
-		testIntegerObject(t, pair.Value, expectedValue)
+		testIntegerObject(t, value, expected[i].value)
+	}
+
+	if result.Inspect() != "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}" {
+		t.Errorf("Hash.Inspect() wrong. got=%q", result.Inspect())
 	}
 }
 
@@ -582,6 +686,241 @@ func TestHashIndexExpressions(t *testing.T) {
 		}
 	}
 }
+
+func TestHashKeyCollisions(t *testing.T) {
+	// 모든 문자열의 HashKey 가 같아지도록 해시 함수를 바꾼다
+	original := object.StringHash
+	object.StringHash = func(string) uint64 { return 0 }
+	defer func() { object.StringHash = original }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 1, "bar": 2}["foo"]`, 1},
+		{`{"foo": 1, "bar": 2}["bar"]`, 2},
+		{`{"foo": 1, "bar": 2}["baz"]`, nil},
+		{`len({"foo": 1, "bar": 2, "foo": 3})`, 2},
+		{`{"foo": 1, "bar": 2, "foo": 3}["foo"]`, 3},
+		{`has(delete({"foo": 1, "bar": 2}, "foo"), "bar")`, true},
+		{`has(delete({"foo": 1, "bar": 2}, "foo"), "foo")`, false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestRecursionDepthLimit(t *testing.T) {
+	input := `
+let countdown = fn(x) {
+  countdown(x + 1);
+};
+countdown(0);`
+
+	evaluated := testEval(input)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+
+	expected := "maximum recursion depth 10000 exceeded"
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q",
+			expected, errObj.Message)
+	}
+}
+
+func TestConfigurableRecursionDepth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let f = fn(x) { if (x == 0) { 0 } else { f(x - 1) } }; f(19);", 0},
+		{"let f = fn(x) { if (x == 0) { 0 } else { f(x - 1) } }; f(20);",
+			"maximum recursion depth 20 exceeded"},
+		{"let f = fn(x) { if (x == 0) { 0 } else { f(x - 1) } }; f(5); f(19);", 0},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalContext(context.Background(), tt.input, Options{MaxDepth: 20})
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T(%+v)",
+					evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+		}
+	}
+}
+
+// version 은 Comparable 을 구현하는 호스트 정의 타입의 예시
+type version struct{ major, minor int64 }
+
+func (v *version) Type() object.ObjectType { return "VERSION" }
+func (v *version) Inspect() string         { return fmt.Sprintf("v%d.%d", v.major, v.minor) }
+func (v *version) Compare(other object.Object) (int, bool) {
+	o, ok := other.(*version)
+	if !ok {
+		return 0, false
+	}
+	if v.major != o.major {
+		return int(v.major - o.major), true
+	}
+	return int(v.minor - o.minor), true
+}
+
+// counter 는 Callable 을 구현하는 호스트 정의 타입의 예시
+type counter struct{ calls int64 }
+
+func (c *counter) Type() object.ObjectType { return "COUNTER" }
+func (c *counter) Inspect() string         { return "counter" }
+func (c *counter) Call(args ...object.Object) object.Object {
+	c.calls++
+	return &object.Integer{Value: c.calls}
+}
+
+func TestNativeObjects(t *testing.T) {
+	newEnv := func() *object.Environment {
+		env := object.NewEnvironment()
+		env.Set("db", &object.Native{
+			TypeName:   "DB",
+			Value:      "main",
+			Properties: map[string]object.Object{"name": &object.String{Value: "main"}},
+			Methods: map[string]object.Method{
+				"query": func(self *object.Native, args ...object.Object) object.Object {
+					return &object.String{Value: self.Value.(string) + ":" + args[0].Inspect()}
+				},
+			},
+		})
+		env.Set("other", &object.Native{TypeName: "DB", Value: "other"})
+		env.Set("older", &version{1, 2})
+		env.Set("newer", &version{1, 10})
+		env.Set("next", &counter{})
+		return env
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`db["name"]`, "main"},
+		{`db["query"]("users")`, "main:users"},
+		{`let q = db["query"]; q("a") + q("b")`, "main:amain:b"},
+		{`db["missing"]`, nil},
+		{`db[1]`, "ERROR: unusable as DB key: INTEGER"},
+		{`db == db`, true},
+		{`db == other`, false},
+		{`db != other`, true},
+		{`db == 1`, false},
+		{`db < other`, "ERROR: cannot compare: DB < DB"},
+		{`older < newer`, true},
+		{`older > newer`, false},
+		{`older == older`, true},
+		{`older != newer`, true},
+		{`older < 1`, "ERROR: cannot compare: VERSION < INTEGER"},
+		{`next(); next(); next()`, 3},
+		{`db`, "<DB {name: main}>"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		evaluated := Eval(p.ParseProgram(), newEnv())
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			if evaluated == nil || evaluated.Inspect() != expected {
+				t.Errorf("wrong result for %q. expected=%q, got=%+v",
+					tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestFrozenEnvironment(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Freeze()
+
+	l := lexer.New("let x = 1;")
+	p := parser.New(l)
+	evaluated := Eval(p.ParseProgram(), env)
+
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "cannot bind x: environment is frozen" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+// TestConcurrentEval 은 얼린 전역 환경을 공유하면서 여러 프로그램을 동시에 평가한다.
+// go test -race 로 실행하면 데이터 경쟁이 없는지 확인할 수 있다.
+func TestConcurrentEval(t *testing.T) {
+	globals := object.NewEnvironment()
+	setup := `
+let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+let double = fn(x) { x * 2 };
+let names = {"a": 1, "b": 2};`
+	Eval(parser.New(lexer.New(setup)).ParseProgram(), globals)
+	globals.Freeze()
+
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"fib(15)", 610},
+		{"let x = double(21); x", 42},
+		{`names["a"] + names["b"]`, 3},
+		{"let fib = fn(n) { n }; fib(15)", 15},
+		{"let x = [1, 2, 3]; len(push(x, double(2)))", 4},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		tt := tests[i%len(tests)]
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			env := object.NewEnclosedEnvironment(globals)
+			testIntegerObject(t, Eval(program, env), tt.expected)
+		}()
+	}
+	wg.Wait()
+
+	// 각 실행의 let 바인딩은 전역 환경에 남지 않는다
+	if _, ok := globals.Get("x"); ok {
+		t.Errorf("x leaked into frozen globals")
+	}
+}
+
 func testEval(input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
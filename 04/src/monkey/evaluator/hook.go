@@ -0,0 +1,59 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"monkey/ast"
+	"monkey/object"
+)
+
+// Hook 은 평가가 진행되는 과정을 관찰하는 인터페이스로, 디버거나 프로파일러 같은 도구가 구현한다.
+// Options.Hook 으로 설정하며, 설정하지 않으면 평가에는 nil 비교 외의 비용이 들지 않는다.
+type Hook interface {
+	// Statement 는 file 의 문 stmt 를 env 에서 평가하기 직전에 호출된다. file 은 파일 없이 평가하면 빈 문자열이다.
+	// nil 이 아닌 에러를 반환하면 평가를 멈추고 그 에러를 Cause 로 하는 에러 객체를 반환한다.
+	Statement(file string, stmt ast.Statement, env *object.Environment) error
+	// Enter 는 함수를 호출하기 직전에 호출된다. 내장함수와 호스트가 정의한 객체의 호출도 포함한다.
+	Enter(fn object.Object, args []object.Object)
+	// Exit 는 Enter 로 알린 호출이 끝난 직후에 호출된다.
+	Exit(fn object.Object, result object.Object)
+}
+
+// ForkHook 을 구현한 Hook 은 spawn 으로 만든 태스크마다 Fork 가 반환한 Hook 을 사용한다.
+// Fork 가 nil 을 반환하면 그 태스크는 관찰하지 않는다.
+// ForkHook 을 구현하지 않은 Hook 은 여러 고루틴에서 동시에 호출될 수 있다.
+type ForkHook interface {
+	Hook
+	Fork() Hook
+}
+
+// BranchHook 을 구현한 Hook 은 if 식이 어느 쪽 블록을 실행하는지도 알림을 받는다.
+type BranchHook interface {
+	Hook
+	// Branch 는 file 의 if 식 node 의 조건을 평가한 직후에 호출된다.
+	// taken 이 true 이면 Consequence 를, false 이면 Alternative 를 실행한다. Alternative 가 없다면 아무것도 실행하지 않는다.
+	Branch(file string, node *ast.IfExpression, taken bool)
+}
+
+// setHook 은 state 가 h 로 평가를 관찰하게 한다
+func (s *state) setHook(h Hook) {
+	s.hook = h
+	s.branches, _ = h.(BranchHook)
+}
+
+// ErrHookStopped 는 Hook 이 에러를 반환해 평가를 멈췄을 때 에러 객체의 Cause 가 감싸는 에러이다.
+// Cause 는 Hook 이 반환한 에러도 함께 감싸므로 debug.ErrQuit 같은 에러도 errors.Is 로 확인할 수 있다
+var ErrHookStopped = errors.New("evaluation stopped by hook")
+
+// hookError 는 Hook 이 반환한 에러로 평가를 멈출 때의 에러 객체를 만든다
+func hookError(err error) *object.Error {
+	return &object.Error{Message: err.Error(), Cause: fmt.Errorf("%w: %w", ErrHookStopped, err)}
+}
+
+// forkHook 은 spawn 으로 만든 태스크가 사용할 Hook 을 반환한다
+func forkHook(h Hook) Hook {
+	if f, ok := h.(ForkHook); ok {
+		return f.Fork()
+	}
+	return h
+}
@@ -0,0 +1,160 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingHook 은 받은 이벤트를 문자열로 기록한다
+type recordingHook struct {
+	events []string
+	stopAt int // 0 이 아니면 이 줄의 문에서 평가를 멈춘다
+}
+
+var errStopped = errors.New("stopped by hook")
+
+func (h *recordingHook) Statement(file string, stmt ast.Statement, env *object.Environment) error {
+	h.events = append(h.events, fmt.Sprintf("stmt %d:%d", stmt.Pos().Line, stmt.Pos().Column))
+	if stmt.Pos().Line == h.stopAt {
+		return errStopped
+	}
+	return nil
+}
+
+func (h *recordingHook) Enter(fn object.Object, args []object.Object) {
+	h.events = append(h.events, "enter "+hookName(fn))
+}
+
+func (h *recordingHook) Exit(fn object.Object, result object.Object) {
+	h.events = append(h.events, "exit "+hookName(fn)+" = "+result.Inspect())
+}
+
+func hookName(fn object.Object) string {
+	switch fn := fn.(type) {
+	case *object.Function:
+		return fn.Name
+	case *object.Builtin:
+		return "builtin"
+	}
+	return string(fn.Type())
+}
+
+func TestHook(t *testing.T) {
+	input := `let add = fn(a, b) {
+  a + b
+};
+let x = len("ab");
+add(x, 1);`
+
+	hook := &recordingHook{}
+	result := testEvalContext(context.Background(), input, Options{Hook: hook})
+	testIntegerObject(t, result, 3)
+
+	expected := []string{
+		"stmt 1:1",
+		"stmt 4:1",
+		"enter builtin",
+		"exit builtin = 2",
+		"stmt 5:1",
+		"enter add",
+		"stmt 2:3",
+		"exit add = 3",
+	}
+	if strings.Join(hook.events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong events.\nexpected=%q\ngot=     %q", expected, hook.events)
+	}
+}
+
+func TestHookStop(t *testing.T) {
+	input := `let f = fn() {
+  1
+};
+f();
+2;`
+
+	hook := &recordingHook{stopAt: 2}
+	result := testEvalContext(context.Background(), input, Options{Hook: hook})
+
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", result, result)
+	}
+	if !errors.Is(errObj, errStopped) {
+		t.Errorf("wrong cause. got=%v", errObj.Cause)
+	}
+	if last := hook.events[len(hook.events)-1]; last != "exit f = ERROR: stopped by hook" {
+		t.Errorf("wrong last event. got=%q", last)
+	}
+}
+
+// branchHook 은 if 식이 실행한 쪽을 기록한다
+type branchHook struct {
+	recordingHook
+}
+
+func (h *branchHook) Branch(file string, node *ast.IfExpression, taken bool) {
+	h.events = append(h.events, fmt.Sprintf("branch %s %t", node.Pos(), taken))
+}
+
+func TestBranchHook(t *testing.T) {
+	input := `let f = fn(x) { if (x > 0) { 1 } };
+f(1);
+f(0);`
+
+	hook := &branchHook{}
+	testEvalContext(context.Background(), input, Options{Hook: hook})
+
+	var branches []string
+	for _, event := range hook.events {
+		if strings.HasPrefix(event, "branch") {
+			branches = append(branches, event)
+		}
+	}
+	expected := []string{"branch 1:17 true", "branch 1:17 false"}
+	if strings.Join(branches, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong branch events.\nexpected=%q\ngot=     %q", expected, branches)
+	}
+}
+
+// fileHook 은 문이 속한 파일의 이름을 기록한다
+type fileHook struct {
+	recordingHook
+	files []string
+}
+
+func (h *fileHook) Statement(file string, stmt ast.Statement, env *object.Environment) error {
+	h.files = append(h.files, fmt.Sprintf("%s %d:%d", filepath.Base(file), stmt.Pos().Line, stmt.Pos().Column))
+	return nil
+}
+
+func TestHookFunctionFile(t *testing.T) {
+	input := `let math = import "math.mk";
+math["add"](1, 2);`
+
+	hook := &fileHook{}
+	program := parser.New(lexer.New(input)).ParseProgram()
+	EvalContext(context.Background(), program, object.NewEnvironment(), Options{
+		Modules:  NewModuleLoader(),
+		Filename: filepath.Join("testdata", "modules", "main.mk"),
+		Hook:     hook,
+	})
+
+	expected := []string{
+		"main.mk 1:1",
+		"math.mk 1:1", "math.mk 3:1", "math.mk 5:1",
+		"main.mk 2:1",
+		// 다른 파일에서 정의된 함수의 본문은 그 파일의 문으로 알린다
+		"math.mk 6:3", "math.mk 7:3",
+	}
+	if strings.Join(hook.files, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong statement files.\nexpected=%q\ngot=     %q", expected, hook.files)
+	}
+}
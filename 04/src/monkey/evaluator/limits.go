@@ -0,0 +1,242 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"monkey/ast"
+	"monkey/object"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// 실행 제한에 걸려 평가가 중단되었을 때 반환되는 에러 객체의 Cause.
+// 호스트는 errors.Is 로 어떤 제한에 걸렸는지 구분할 수 있다.
+// 컨텍스트가 취소되거나 시간이 초과된 경우에는 ctx.Err() 가 Cause 가 된다.
+var (
+	ErrMaxDepth          = errors.New("maximum recursion depth exceeded")
+	ErrMaxSteps          = errors.New("maximum step count exceeded")
+	ErrMaxAllocations    = errors.New("maximum allocation count exceeded")
+	ErrMaxCollectionSize = errors.New("maximum collection size exceeded")
+)
+
+// contextCheckInterval 만큼의 단계마다 컨텍스트가 취소되었는지 확인한다.
+const contextCheckInterval = 64
+
+// Options 는 EvalContext 로 평가할 때 적용할 실행 제한을 정한다.
+// MaxDepth 를 제외한 필드는 0 이면 제한하지 않는다.
+type Options struct {
+	// MaxDepth 는 함수 호출의 최대 깊이. 0 이면 DefaultMaxRecursionDepth 를 사용하고, 음수라면 제한하지 않는다.
+	MaxDepth int
+	// MaxSteps 는 평가할 수 있는 AST 노드의 최대 개수
+	MaxSteps int64
+	// Timeout 은 평가에 허용되는 최대 시간
+	Timeout time.Duration
+	// MaxAllocations 는 평가 중에 새로 만들 수 있는 객체의 최대 개수
+	MaxAllocations int64
+	// MaxCollectionSize 는 문자열의 길이, 배열과 해시의 원소 개수의 최대값
+	MaxCollectionSize int
+
+	// Modules 는 import 할 모듈을 찾고 캐시하는 로더. nil 이면 DefaultModuleLoader 를 사용한다.
+	Modules *ModuleLoader
+	// Builtins 는 모듈을 평가하는 환경의 바깥 환경. 호스트가 등록한 내장 함수를 모듈에서도 사용할 수 있게 한다.
+	// 모듈의 결과는 Modules 에 캐시되므로 Builtins 가 다른 평가들은 서로 다른 ModuleLoader 를 사용해야 한다.
+	Builtins *object.Environment
+	// Stdout 은 puts 가 출력할 곳. nil 이면 os.Stdout 에 출력한다
+	Stdout io.Writer
+	// Filename 은 평가할 프로그램의 파일 경로. import 의 상대 경로는 이 파일의 디렉터리를 기준으로 찾는다.
+	Filename string
+
+	// Hook 은 평가의 진행을 관찰한다. nil 이면 관찰하지 않는다.
+	Hook Hook
+}
+
+// EvalContext 는 Eval 과 같지만 ctx 가 취소되거나 opts 의 제한을 넘으면 평가를 멈추고
+// Cause 가 설정된 에러 객체를 반환한다. 신뢰할 수 없는 스크립트를 평가하는 호스트를 위한 진입점이다.
+func EvalContext(
+	ctx context.Context,
+	node ast.Node,
+	env *object.Environment,
+	opts Options,
+) object.Object {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// context.Background 처럼 호스트가 취소할 수 없는 컨텍스트는 매 단계마다 확인할 필요가 없다
+	cancelable := ctx.Done() != nil
+
+	// 평가가 끝나면 spawn 한 TASK 들도 멈추도록 항상 취소할 수 있는 컨텍스트에서 평가한다
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := newState(opts)
+	s.ctx = ctx
+	s.cancelable = cancelable
+	s.limited = cancelable || opts.MaxSteps > 0
+
+	// 남은 TASK 들이 교착 상태가 아니라 취소로 끝나도록 먼저 취소한 뒤에 빠져나간다
+	s.sched.Enter()
+	defer func() {
+		cancel()
+		s.sched.Exit()
+	}()
+
+	return s.eval(node, env)
+}
+
+// limits 는 실행 제한과 지금까지 사용한 양을 담는다.
+// spawn 으로 만든 고루틴들도 같은 제한을 나누어 쓰므로 카운터는 atomic 으로 갱신한다.
+type limits struct {
+	steps             atomic.Int64
+	maxSteps          int64
+	allocations       atomic.Int64
+	maxAllocations    int64
+	maxCollectionSize int
+}
+
+func newState(opts Options) *state {
+	s := &state{
+		maxDepth: opts.MaxDepth,
+		limits: &limits{
+			maxSteps:          opts.MaxSteps,
+			maxAllocations:    opts.MaxAllocations,
+			maxCollectionSize: opts.MaxCollectionSize,
+		},
+		modules:  opts.Modules,
+		builtins: opts.Builtins,
+		stdout:   opts.Stdout,
+		importer: &importer{},
+		sched:    object.NewScheduler(),
+	}
+	s.setHook(opts.Hook)
+	if s.maxDepth == 0 {
+		s.maxDepth = DefaultMaxRecursionDepth
+	}
+	if s.modules == nil {
+		s.modules = DefaultModuleLoader
+	}
+	if opts.Filename != "" {
+		if abs, err := filepath.Abs(opts.Filename); err == nil {
+			s.file = abs
+			s.importing = []string{abs}
+		}
+	}
+	return s
+}
+
+// fork 는 다른 고루틴에서 평가를 이어가기 위한 state 를 만든다.
+// 호출 깊이는 새로 시작하지만 컨텍스트와 실행 제한은 부모와 공유한다.
+func (s *state) fork() *state {
+	return &state{
+		maxDepth:   s.maxDepth,
+		ctx:        s.ctx,
+		cancelable: s.cancelable,
+		limited:    s.limited,
+		limits:     s.limits,
+
+		modules:   s.modules,
+		builtins:  s.builtins,
+		stdout:    s.stdout,
+		file:      s.file,
+		importing: s.importing,
+		importer:  &importer{},
+		sched:     s.sched,
+
+		hook:     s.hook,
+		branches: s.branches,
+	}
+}
+
+// done 은 평가가 취소되면 닫히는 채널을 반환한다. 취소될 수 없다면 nil 을 반환한다.
+func (s *state) done() <-chan struct{} {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Done()
+}
+
+// step 은 노드 하나를 평가하기 전에 호출되어 단계 수와 컨텍스트를 확인한다.
+func (s *state) step() *object.Error {
+	return s.stepN(1)
+}
+
+// stepN 은 n 단계를 한 번에 세고 단계 수와 컨텍스트를 확인한다.
+// 원소를 많이 만드는 내장함수가 원소들을 만드는 일도 평가의 단계로 세기 위해 사용한다.
+func (s *state) stepN(n int64) *object.Error {
+	steps := s.limits.steps.Add(n)
+
+	if s.limits.maxSteps > 0 && steps > s.limits.maxSteps {
+		return newLimitError(ErrMaxSteps,
+			"maximum step count %d exceeded", s.limits.maxSteps)
+	}
+
+	if s.ctx != nil && (steps-n)/contextCheckInterval != steps/contextCheckInterval {
+		if err := s.ctx.Err(); err != nil {
+			return contextError(err)
+		}
+	}
+
+	return nil
+}
+
+// track 은 새로 만들어진 객체를 세고 크기를 확인한다.
+// 제한을 넘었다면 obj 대신 에러 객체를, 그렇지 않다면 obj 를 그대로 반환한다.
+func (s *state) track(obj object.Object) object.Object {
+	l := s.limits
+	if l.maxAllocations == 0 && l.maxCollectionSize == 0 {
+		return obj
+	}
+
+	var size int
+	switch obj := obj.(type) {
+	case nil, *object.Null, *object.Boolean, *object.Error, *object.ReturnValue:
+		// 싱글턴이거나 값을 감싸기만 하는 객체는 새로 할당된 것으로 보지 않는다
+		return obj
+	case *object.String:
+		size = len(obj.Value)
+	case *object.Array:
+		size = len(obj.Elements)
+	case *object.Hash:
+		size = obj.Len()
+	}
+
+	if l.maxCollectionSize > 0 && size > l.maxCollectionSize {
+		return newLimitError(ErrMaxCollectionSize,
+			"maximum collection size %d exceeded: %s of size %d",
+			l.maxCollectionSize, obj.Type(), size)
+	}
+
+	allocations := l.allocations.Add(1)
+	if l.maxAllocations > 0 && allocations > l.maxAllocations {
+		return newLimitError(ErrMaxAllocations,
+			"maximum allocation count %d exceeded", l.maxAllocations)
+	}
+
+	return obj
+}
+
+// reserve 는 크기가 size 인 typ 객체를 할당하기 전에 MaxCollectionSize 를 넘는지 확인한다.
+// 큰 객체는 할당한 뒤에 track 으로 확인하면 이미 메모리를 다 쓴 뒤이므로, 크기를 미리 알 수 있는 내장함수가 사용한다.
+func (s *state) reserve(typ object.ObjectType, size int64) *object.Error {
+	if max := s.limits.maxCollectionSize; max > 0 && size > int64(max) {
+		return newLimitError(ErrMaxCollectionSize,
+			"maximum collection size %d exceeded: %s of size %d", max, typ, size)
+	}
+	return nil
+}
+
+func newLimitError(cause error, format string, a ...interface{}) *object.Error {
+	err := newError(format, a...)
+	err.Cause = cause
+	return err
+}
+
+func contextError(err error) *object.Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newLimitError(err, "evaluation timed out")
+	}
+	return newLimitError(err, "evaluation cancelled")
+}
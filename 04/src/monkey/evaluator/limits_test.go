@@ -0,0 +1,163 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"testing"
+	"time"
+)
+
+func TestEvalContextLimits(t *testing.T) {
+	loop := `
+let loop = fn(x) { loop(x + 1) };
+loop(0);`
+
+	tests := []struct {
+		input   string
+		opts    Options
+		cause   error
+		message string
+	}{
+		{
+			loop,
+			Options{MaxDepth: 100},
+			ErrMaxDepth,
+			"maximum recursion depth 100 exceeded",
+		},
+		{
+			loop,
+			Options{MaxSteps: 50},
+			ErrMaxSteps,
+			"maximum step count 50 exceeded",
+		},
+		{
+			`let grow = fn(arr) { grow(push(arr, 1)) }; grow([]);`,
+			Options{MaxCollectionSize: 10},
+			ErrMaxCollectionSize,
+			"maximum collection size 10 exceeded: ARRAY of size 11",
+		},
+		{
+			`let double = fn(s) { double(s + s) }; double("a");`,
+			Options{MaxCollectionSize: 1000},
+			ErrMaxCollectionSize,
+			"maximum collection size 1000 exceeded: STRING of size 1024",
+		},
+		{
+			`let a = [1, 2, 3]; let b = [4, 5, 6]; [a, b];`,
+			Options{MaxAllocations: 8},
+			ErrMaxAllocations,
+			"maximum allocation count 8 exceeded",
+		},
+		{
+			`range(0, 1099511627776)`,
+			Options{MaxCollectionSize: 1000},
+			ErrMaxCollectionSize,
+			"maximum collection size 1000 exceeded: ARRAY of size 1099511627776",
+		},
+		{
+			`replace(repeat("a", 5000), "", repeat("b", 5000))`,
+			Options{MaxCollectionSize: 10000},
+			ErrMaxCollectionSize,
+			"maximum collection size 10000 exceeded: STRING of size 25010000",
+		},
+		{
+			`let s = repeat("b", 5000); join([s, s, s], s)`,
+			Options{MaxCollectionSize: 20000},
+			ErrMaxCollectionSize,
+			"maximum collection size 20000 exceeded: STRING of size 25000",
+		},
+		{
+			`repeat("ab", 1099511627776)`,
+			Options{MaxCollectionSize: 1000},
+			ErrMaxCollectionSize,
+			"maximum collection size 1000 exceeded: STRING of size 2199023255552",
+		},
+		{
+			`range(1000000)`,
+			Options{MaxSteps: 1000},
+			ErrMaxSteps,
+			"maximum step count 1000 exceeded",
+		},
+		{
+			loop,
+			Options{MaxDepth: 1000000, Timeout: 10 * time.Millisecond},
+			context.DeadlineExceeded,
+			"evaluation timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalContext(context.Background(), tt.input, tt.opts)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)",
+				evaluated, evaluated)
+			continue
+		}
+
+		if !errors.Is(errObj, tt.cause) {
+			t.Errorf("wrong error cause. expected=%v, got=%v",
+				tt.cause, errObj.Cause)
+		}
+
+		if errObj.Message != tt.message {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.message, errObj.Message)
+		}
+	}
+}
+
+func TestEvalContextWithinLimits(t *testing.T) {
+	input := `
+let sum = fn(x) { if (x == 0) { 0 } else { x + sum(x - 1) } };
+sum(100);`
+
+	opts := Options{
+		MaxSteps:          10000,
+		Timeout:           time.Second,
+		MaxAllocations:    10000,
+		MaxCollectionSize: 10,
+	}
+
+	testIntegerObject(t, testEvalContext(context.Background(), input, opts), 5050)
+}
+
+func TestEvalContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 내장함수가 많은 원소를 만드는 동안에도 취소를 확인한다
+	for _, input := range []string{
+		"let loop = fn() { loop() }; loop();",
+		"range(1099511627776);",
+	} {
+		evaluated := testEvalContext(ctx, input, Options{MaxDepth: 1000000})
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Fatalf("no error object returned for %q. got=%T(%+v)", input, evaluated, evaluated)
+		}
+
+		if !errors.Is(errObj, context.Canceled) {
+			t.Errorf("wrong error cause for %q. expected=%v, got=%v",
+				input, context.Canceled, errObj.Cause)
+		}
+
+		if errObj.Message != "evaluation cancelled" {
+			t.Errorf("wrong error message for %q. got=%q", input, errObj.Message)
+		}
+	}
+}
+
+func testEvalContext(ctx context.Context, input string, opts Options) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+
+	return EvalContext(ctx, program, env, opts)
+}
@@ -0,0 +1,245 @@
+package evaluator
+
+import (
+	"errors"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// 모듈 시스템. import "path/to/mod.mk" 는 모듈 파일을 새로운 환경에서 평가하고,
+// 모듈의 최상위 let 바인딩을 이름을 키로 하는 해시로 반환한다.
+//
+//	let math = import "lib/math.mk";
+//	math["add"](1, 2);
+
+// DefaultModuleLoader 는 Options.Modules 를 지정하지 않았을 때 사용하는 모듈 로더이다.
+// 검색 경로는 MONKEYPATH 환경 변수에서 읽는다.
+var DefaultModuleLoader = NewModuleLoader(filepath.SplitList(os.Getenv("MONKEYPATH"))...)
+
+// ModuleLoader 는 import 할 모듈 파일을 찾고, 한 번 평가한 모듈을 캐시한다.
+// 같은 모듈을 여러 번 import 해도 모듈은 한 번만 평가되고 같은 해시 객체가 반환된다.
+// 여러 고루틴에서 동시에 사용할 수 있다.
+type ModuleLoader struct {
+	// Paths 는 모듈 검색 경로. 상대 경로는 import 하는 파일의 디렉터리에서 먼저 찾고, 그 다음 Paths 를 순서대로 찾는다.
+	Paths []string
+
+	mu      sync.Mutex
+	modules map[string]*module // 절대 경로를 키로 한다
+}
+
+// module 은 평가 중이거나 평가가 끝난 모듈. 평가가 끝나면 done 이 닫힌다.
+type module struct {
+	path     string
+	done     chan struct{}
+	result   object.Object
+	importer *importer // 모듈을 평가하는 고루틴
+}
+
+// importer 는 모듈을 평가하는 고루틴 하나. 고루틴마다 평가 중인 모듈들의 경로는 state.importing 에 있지만,
+// 다른 고루틴이 평가 중인 모듈을 기다릴 때 순환 import 로 서로를 기다리게 되는지는 ModuleLoader 에서 확인해야 하므로
+// 기다리는 동안 무엇을 기다리는지를 여기에 남긴다. 필드는 ModuleLoader.mu 를 잠근 채로 읽고 쓴다.
+type importer struct {
+	waitsOn   *module  // 기다리는 모듈. 기다리지 않는다면 nil
+	importing []string // 기다리기 시작했을 때 평가 중이던 모듈들의 경로
+}
+
+// NewModuleLoader 는 paths 를 검색 경로로 하는 ModuleLoader 를 만든다.
+func NewModuleLoader(paths ...string) *ModuleLoader {
+	return &ModuleLoader{
+		Paths:   paths,
+		modules: make(map[string]*module),
+	}
+}
+
+// Resolve 는 dir 에 있는 파일에서 import 한 path 가 가리키는 모듈 파일의 절대 경로를 찾는다.
+// dir 이 비어 있다면 현재 작업 디렉터리를 기준으로 한다.
+func (l *ModuleLoader) Resolve(path, dir string) (string, error) {
+	var candidates []string
+	if filepath.IsAbs(path) {
+		candidates = []string{path}
+	} else {
+		candidates = append(candidates, filepath.Join(dir, path))
+		// ./ 이나 ../ 로 시작하는 경로는 import 하는 파일을 기준으로만 찾는다
+		if !isExplicitRelative(path) {
+			for _, p := range l.Paths {
+				candidates = append(candidates, filepath.Join(p, path))
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		return filepath.Abs(candidate)
+	}
+
+	return "", errors.New("module not found: \"" + path + "\"")
+}
+
+func isExplicitRelative(path string) bool {
+	path = filepath.ToSlash(path)
+	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// evalImportExpression 은 모듈을 찾아 평가하고 모듈의 바인딩을 담은 해시를 반환한다.
+func (s *state) evalImportExpression(node *ast.ImportExpression) object.Object {
+	path, err := s.modules.Resolve(node.Path.Value, filepath.Dir(s.file))
+	if err != nil {
+		return newError("%s", err)
+	}
+
+	// import 하는 중인 모듈을 다시 import 하면 순환 참조이다
+	for i, importing := range s.importing {
+		if importing == path {
+			return newError("import cycle: %s", formatImportCycle(append(s.importing[i:], path)))
+		}
+	}
+
+	return s.modules.load(s, path)
+}
+
+// load 는 path 의 모듈을 평가한 결과를 반환한다. 다른 고루틴이 같은 모듈을 평가하고 있다면 끝날 때까지 기다린다.
+// 기다리면 순환 import 로 고루틴들이 서로를 영원히 기다리게 되는 경우에는 기다리지 않고 에러를 반환한다.
+// 평가가 에러로 끝난 모듈은 캐시하지 않는다. 실행 제한이나 취소로 멈춘 에러는 그 평가에만 해당하므로,
+// 기다리던 고루틴은 그 에러를 받는 대신 모듈을 다시 평가한다.
+func (l *ModuleLoader) load(s *state, path string) object.Object {
+	for {
+		l.mu.Lock()
+		m, ok := l.modules[path]
+		if !ok {
+			m = &module{path: path, done: make(chan struct{}), importer: s.importer}
+			l.modules[path] = m
+			l.mu.Unlock()
+
+			m.result = s.evalModule(path)
+			if isError(m.result) {
+				l.mu.Lock()
+				delete(l.modules, path)
+				l.mu.Unlock()
+			}
+			close(m.done)
+			return m.result
+		}
+		if cycle := s.importCycle(m); cycle != nil {
+			l.mu.Unlock()
+			return newError("import cycle: %s", formatImportCycle(cycle))
+		}
+		s.importer.waitsOn = m
+		s.importer.importing = s.importing
+		l.mu.Unlock()
+
+		var canceled bool
+		select {
+		case <-m.done:
+		case <-s.done():
+			canceled = true
+		}
+
+		l.mu.Lock()
+		s.importer.waitsOn = nil
+		l.mu.Unlock()
+
+		if canceled {
+			return contextError(s.ctx.Err())
+		}
+		if errObj, ok := m.result.(*object.Error); !ok || errObj.Cause == nil {
+			return m.result
+		}
+	}
+}
+
+// importCycle 은 s 가 다른 고루틴이 평가 중인 m 을 기다리면 순환 import 가 되는지 확인하고, 그렇다면 순환하는 경로를 반환한다.
+// m 을 평가하는 고루틴이 기다리는 모듈을 차례로 따라가서 s 의 고루틴이 평가 중인 모듈에 닿으면 순환이다.
+// 기다리기 전에 항상 이렇게 확인하므로 기다리는 관계에는 순환이 없고, 따라가기는 반드시 끝난다.
+// ModuleLoader.mu 를 잠근 채로 호출해야 한다.
+func (s *state) importCycle(m *module) []string {
+	var hops []string
+	for cur := m; ; cur = cur.importer.waitsOn {
+		select {
+		case <-cur.done:
+			return nil
+		default:
+		}
+
+		imp := cur.importer
+		if imp == s.importer {
+			i := slices.Index(s.importing, cur.path)
+			if i < 0 {
+				return nil
+			}
+			return slices.Concat(s.importing[i:], hops, []string{cur.path})
+		}
+		if imp.waitsOn == nil {
+			return nil
+		}
+		i := slices.Index(imp.importing, cur.path)
+		if i < 0 {
+			return nil
+		}
+		hops = append(hops, imp.importing[i:]...)
+	}
+}
+
+// evalModule 은 모듈 파일을 새로운 환경에서 평가한다. 실행 제한은 import 한 쪽과 공유한다.
+func (s *state) evalModule(path string) object.Object {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return newError("cannot read module %s: %s", path, err)
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return newError("parser errors in module %s: %s", path, strings.Join(p.Errors(), "; "))
+	}
+
+	ms := s.fork()
+	ms.depth = s.depth
+	ms.importer = s.importer
+	ms.file = path
+	ms.importing = append(append([]string{}, s.importing...), path)
+
+	env := object.NewEnvironment()
+	if s.builtins != nil {
+		env = object.NewEnclosedEnvironment(s.builtins)
+	}
+	if result := ms.eval(program, env); isError(result) {
+		return result
+	}
+
+	return moduleExports(program, env)
+}
+
+// moduleExports 는 모듈의 최상위 let 바인딩을 이름을 키로 하는 해시로 만든다. 키는 처음 정의된 순서를 따른다.
+func moduleExports(program *ast.Program, env *object.Environment) *object.Hash {
+	exports := object.NewHash()
+
+	for _, statement := range program.Statements {
+		let, ok := statement.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+
+		value, _ := env.Get(let.Name.Value)
+		exports.Set(&object.String{Value: let.Name.Value}, value)
+	}
+
+	return exports
+}
+
+func formatImportCycle(paths []string) string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = filepath.Base(path)
+	}
+	return strings.Join(names, " -> ")
+}
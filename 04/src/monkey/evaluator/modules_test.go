@@ -0,0 +1,221 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testEvalModule 은 모듈 fixture 들이 있는 디렉터리의 파일에서 평가하는 것처럼 input 을 평가한다.
+func testEvalModule(input string, loader *ModuleLoader) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+
+	opts := Options{
+		Modules:  loader,
+		Filename: filepath.Join("testdata", "modules", "main.mk"),
+	}
+	return EvalContext(context.Background(), program, env, opts)
+}
+
+func TestImportExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`let math = import "math.mk"; math["pi"]`, 3},
+		{`let math = import "math.mk"; math["add"](2, 3)`, 5},
+		{`let math = import "./math.mk"; math["square"](4)`, 16},
+		{`import "math.mk"["square"](5)`, 25},
+		// 모듈이 다른 모듈을 import 할 수 있다
+		{`let geometry = import "geometry.mk"; geometry["area"](2)`, 12},
+		{`let geometry = import "geometry.mk"; geometry["math"]["pi"]`, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalModule(tt.input, NewModuleLoader())
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestModuleExports(t *testing.T) {
+	evaluated := testEvalModule(`import "math.mk"`, NewModuleLoader())
+	hash, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("import did not return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	// 최상위 let 바인딩만 내보내고, 함수 안의 let 바인딩은 내보내지 않는다
+	expected := []string{"pi", "square", "add"}
+	if hash.Len() != len(expected) {
+		t.Fatalf("module has wrong number of exports. got=%d", hash.Len())
+	}
+	for i, pair := range hash.Pairs() {
+		if pair.Key.Inspect() != expected[i] {
+			t.Errorf("export %d has wrong name. expected=%q, got=%q",
+				i, expected[i], pair.Key.Inspect())
+		}
+	}
+}
+
+func TestModuleCache(t *testing.T) {
+	loader := NewModuleLoader()
+
+	first := testEvalModule(`import "math.mk"`, loader)
+	second := testEvalModule(`import "./math.mk"`, loader)
+	if first != second {
+		t.Errorf("module was evaluated twice. got=%p and %p", first, second)
+	}
+
+	// geometry.mk 가 import 한 math.mk 도 같은 캐시를 사용한다
+	geometry := testEvalModule(`import "geometry.mk"`, loader).(*object.Hash)
+	math, _ := geometry.Get(&object.String{Value: "math"})
+	if math != first {
+		t.Errorf("nested import was evaluated again. got=%p, want=%p", math, first)
+	}
+
+	// 다른 로더는 자신만의 캐시를 갖는다
+	other := testEvalModule(`import "math.mk"`, NewModuleLoader())
+	if other == first {
+		t.Errorf("module was shared between loaders")
+	}
+}
+
+func TestModuleCacheErrors(t *testing.T) {
+	loader := NewModuleLoader()
+	filename := filepath.Join("testdata", "modules", "main.mk")
+	program := parser.New(lexer.New(`import "heavy.mk"["total"]`)).ParseProgram()
+
+	// 실행 제한에 걸려 실패한 import 는 캐시되지 않으므로 제한이 없는 다음 평가에서는 성공한다
+	limited := EvalContext(context.Background(), program, object.NewEnvironment(),
+		Options{Modules: loader, Filename: filename, MaxSteps: 100})
+	if errObj, ok := limited.(*object.Error); !ok || !errors.Is(errObj, ErrMaxSteps) {
+		t.Fatalf("expected max steps error. got=%s", limited.Inspect())
+	}
+
+	evaluated := EvalContext(context.Background(), program, object.NewEnvironment(),
+		Options{Modules: loader, Filename: filename})
+	testIntegerObject(t, evaluated, 499500)
+
+	// 취소된 import 도 캐시되지 않는다
+	loader = NewModuleLoader()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceled := EvalContext(ctx, program, object.NewEnvironment(), Options{Modules: loader, Filename: filename})
+	if errObj, ok := canceled.(*object.Error); !ok || !errors.Is(errObj, context.Canceled) {
+		t.Fatalf("expected canceled error. got=%s", canceled.Inspect())
+	}
+	evaluated = EvalContext(context.Background(), program, object.NewEnvironment(),
+		Options{Modules: loader, Filename: filename})
+	testIntegerObject(t, evaluated, 499500)
+}
+
+func TestModuleSearchPath(t *testing.T) {
+	lib := filepath.Join("testdata", "modules", "lib")
+
+	evaluated := testEvalModule(`import "greeting.mk"["greet"]("Monkey")`, NewModuleLoader(lib))
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+	}
+	if str.Value != "Hello, Monkey!" {
+		t.Errorf("String has wrong value. got=%q", str.Value)
+	}
+
+	// 검색 경로에 없으면 찾지 못한다
+	evaluated = testEvalModule(`import "greeting.mk"`, NewModuleLoader())
+	testErrorObject(t, evaluated, `module not found: "greeting.mk"`)
+
+	// ./ 로 시작하는 경로는 검색 경로에서 찾지 않는다
+	evaluated = testEvalModule(`import "./greeting.mk"`, NewModuleLoader(lib))
+	testErrorObject(t, evaluated, `module not found: "./greeting.mk"`)
+}
+
+func TestModuleErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`import "cycle_a.mk"`,
+			"import cycle: cycle_a.mk -> cycle_b.mk -> cycle_a.mk",
+		},
+		{
+			`import "missing.mk"`,
+			`module not found: "missing.mk"`,
+		},
+		{
+			`import "failing.mk"`,
+			"type mismatch: INTEGER + BOOLEAN",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalModule(tt.input, NewModuleLoader())
+		testErrorObject(t, evaluated, tt.expected)
+	}
+
+	evaluated := testEvalModule(`import "broken.mk"`, NewModuleLoader())
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+	}
+	if !strings.HasPrefix(errObj.Message, "parser errors in module ") ||
+		!strings.Contains(errObj.Message, "broken.mk") {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestModuleCycleAcrossGoroutines(t *testing.T) {
+	// 두 고루틴이 서로를 import 하는 모듈들을 동시에 import 하면 서로를 기다리는 대신 순환 import 에러를 반환한다
+	inputs := []string{`import "race_a.mk"`, `import "race_b.mk"`}
+
+	for i := 0; i < 10; i++ {
+		loader := NewModuleLoader()
+		results := make([]object.Object, len(inputs))
+		var wg sync.WaitGroup
+		for j, input := range inputs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				program := parser.New(lexer.New(input)).ParseProgram()
+				opts := Options{
+					Modules:  loader,
+					Filename: filepath.Join("testdata", "modules", "main.mk"),
+					Timeout:  5 * time.Second,
+				}
+				results[j] = EvalContext(context.Background(), program, object.NewEnvironment(), opts)
+			}()
+		}
+		wg.Wait()
+
+		for j, result := range results {
+			errObj, ok := result.(*object.Error)
+			if !ok || !strings.HasPrefix(errObj.Message, "import cycle: ") {
+				t.Fatalf("expected import cycle error for %q. got=%s", inputs[j], result.Inspect())
+			}
+		}
+	}
+}
+
+func testErrorObject(t *testing.T, obj object.Object, expected string) bool {
+	errObj, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("no error object returned. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+		return false
+	}
+	return true
+}
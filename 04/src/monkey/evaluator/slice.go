@@ -0,0 +1,122 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// evalSliceExpression 은 a[start:end:step] 으로 배열이나 문자열의 구간을 새로 만들어 반환한다.
+// 음수 인덱스는 끝에서부터 세고, 범위를 벗어난 인덱스는 양 끝으로 맞춘다.
+// step 이 음수라면 start 에서부터 거꾸로 end 의 직전까지 원소를 모은다. 문자열은 바이트 단위로 자른다.
+func (s *state) evalSliceExpression(
+	node *ast.SliceExpression,
+	env *object.Environment,
+) object.Object {
+	left := s.eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = len(left.Value)
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	// 생략된 값은 nil 로 남겨둔다
+	bounds := make([]*int64, 3)
+	for i, exp := range []ast.Expression{node.Start, node.End, node.Step} {
+		if exp == nil {
+			continue
+		}
+		bound := s.eval(exp, env)
+		if isError(bound) {
+			return bound
+		}
+		integer, ok := bound.(*object.Integer)
+		if !ok {
+			return newError("slice index must be INTEGER, got %s", bound.Type())
+		}
+		bounds[i] = &integer.Value
+	}
+
+	step := int64(1)
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	if step == 0 {
+		return newError("slice step cannot be zero")
+	}
+
+	indices := sliceIndices(length, bounds[0], bounds[1], step)
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, len(indices))
+		for i, idx := range indices {
+			elements[i] = left.Elements[idx]
+		}
+		return s.track(&object.Array{Elements: elements})
+	default:
+		value := left.(*object.String).Value
+		bytes := make([]byte, len(indices))
+		for i, idx := range indices {
+			bytes[i] = value[idx]
+		}
+		return s.track(&object.String{Value: string(bytes)})
+	}
+}
+
+// sliceIndices 는 길이가 length 인 배열에서 [start:end:step] 구간에 속하는 인덱스들을 반환한다.
+// start 와 end 가 nil 이라면 step 의 방향에 따라 처음이나 끝을 사용한다.
+func sliceIndices(length int, start, end *int64, step int64) []int {
+	n := int64(length)
+
+	// step 이 양수라면 인덱스를 [0, n] 로, 음수라면 [-1, n-1] 로 맞춘다
+	lower, upper := int64(0), n
+	if step < 0 {
+		lower, upper = -1, n-1
+	}
+	adjust := func(bound *int64, def int64) int64 {
+		if bound == nil {
+			return def
+		}
+		idx := *bound
+		if idx < 0 {
+			idx += n
+		}
+		if idx < lower {
+			return lower
+		}
+		if idx > upper {
+			return upper
+		}
+		return idx
+	}
+
+	var from, to int64
+	if step > 0 {
+		from, to = adjust(start, lower), adjust(end, upper)
+	} else {
+		from, to = adjust(start, upper), adjust(end, lower)
+	}
+
+	// i += step 이 넘치지 않도록 원소의 개수를 먼저 구한다
+	var count int64
+	switch {
+	case step > 0 && from < to:
+		count = (to-from-1)/step + 1
+	case step < 0 && from > to:
+		count = (from-to-1)/-step + 1
+	}
+
+	indices := make([]int, count)
+	for k := range indices {
+		indices[k] = int(from + int64(k)*step)
+	}
+	return indices
+}
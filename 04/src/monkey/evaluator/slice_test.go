@@ -0,0 +1,72 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3, 4, 5][1:3]`, []int{2, 3}},
+		{`[1, 2, 3, 4, 5][:2]`, []int{1, 2}},
+		{`[1, 2, 3, 4, 5][3:]`, []int{4, 5}},
+		{`[1, 2, 3, 4, 5][:]`, []int{1, 2, 3, 4, 5}},
+		{`[1, 2, 3, 4, 5][-2:]`, []int{4, 5}},
+		{`[1, 2, 3, 4, 5][:-2]`, []int{1, 2, 3}},
+		{`[1, 2, 3, 4, 5][-100:100]`, []int{1, 2, 3, 4, 5}},
+		{`[1, 2, 3, 4, 5][3:1]`, []int{}},
+		{`[1, 2, 3, 4, 5][::2]`, []int{1, 3, 5}},
+		{`[1, 2, 3, 4, 5][1::2]`, []int{2, 4}},
+		{`[1, 2, 3, 4, 5][::-1]`, []int{5, 4, 3, 2, 1}},
+		{`[1, 2, 3, 4, 5][3:0:-1]`, []int{4, 3, 2}},
+		{`[1, 2, 3, 4, 5][-1:-3:-1]`, []int{5, 4}},
+		{`[1, 2, 3, 4, 5][::-2]`, []int{5, 3, 1}},
+		{`[1, 2, 3][::9223372036854775807]`, []int{1}},
+		{`[][1:2]`, []int{}},
+		{`let a = [1, 2, 3]; let i = 1; a[i:i + 1]`, []int{2}},
+		{`"monkey"[1:4]`, "onk"},
+		{`"monkey"[-3:]`, "key"},
+		{`"monkey"[::-1]`, "yeknom"},
+		{`"monkey"[::2]`, "mne"},
+		{`"monkey"[10:]`, ""},
+		{`[1, 2, 3][1:2:0]`, "slice step cannot be zero"},
+		{`[1, 2, 3]["a":]`, "slice index must be INTEGER, got STRING"},
+		{`5[1:2]`, "slice operator not supported: INTEGER"},
+		{`{"a": 1}[1:2]`, "slice operator not supported: HASH"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements for %q. want=%d, got=%d",
+					tt.input, len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q",
+						expected, errObj.Message)
+				}
+				continue
+			}
+			testStringObject(t, evaluated, expected)
+		}
+	}
+}
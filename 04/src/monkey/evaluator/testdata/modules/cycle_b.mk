@@ -0,0 +1 @@
+let a = import "./cycle_a.mk";
@@ -0,0 +1,3 @@
+let math = import "math.mk";
+
+let area = fn(r) { math["pi"] * math["square"](r) };
@@ -0,0 +1 @@
+let total = reduce(range(1000), fn(acc, x) { acc + x }, 0);
@@ -0,0 +1 @@
+let greet = fn(name) { "Hello, " + name + "!" };
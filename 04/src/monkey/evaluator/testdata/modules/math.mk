@@ -0,0 +1,8 @@
+let pi = 3;
+
+let square = fn(x) { x * x };
+
+let add = fn(a, b) {
+  let sum = a + b;
+  sum
+};
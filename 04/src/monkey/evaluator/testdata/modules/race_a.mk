@@ -0,0 +1,2 @@
+let pad = reduce(range(20000), fn(acc, x) { acc + x }, 0);
+let b = import "race_b.mk";
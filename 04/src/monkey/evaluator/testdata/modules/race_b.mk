@@ -0,0 +1,2 @@
+let pad = reduce(range(20000), fn(acc, x) { acc + x }, 0);
+let a = import "race_a.mk";
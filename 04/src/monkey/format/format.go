@@ -0,0 +1,283 @@
+// Package format 은 Monkey 소스 코드를 표준 형식으로 출력하는 pretty-printer 를 제공한다.
+//
+// 출력 형식은 AST 만으로 정해지므로 같은 프로그램은 항상 같은 소스 코드가 되고,
+// 형식을 맞춘 소스 코드를 다시 형식에 맞추어도 바뀌지 않는다.
+//   - 들여쓰기는 공백 두 칸이며, 블록은 항상 여러 줄로 출력한다.
+//   - let 과 return 문은 세미콜론으로 끝낸다. 표현식 문은 블록의 마지막 문이 아니라면 세미콜론으로 끝낸다.
+//   - 괄호는 연산자 우선순위를 지키는 데 필요한 곳에만 출력한다.
+//   - 최상위에서 여러 줄로 출력되는 문의 앞뒤에는 빈 줄을 둔다.
+package format
+
+import (
+	"bytes"
+	"errors"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/token"
+	"strings"
+)
+
+// indent 는 한 단계의 들여쓰기
+const indent = "  "
+
+// Source 는 src 를 파싱하여 표준 형식의 소스 코드로 다시 출력한다.
+// 파싱에 실패하면 파서의 에러 메시지들을 담은 에러를 반환한다.
+func Source(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New("parser errors: " + strings.Join(p.Errors(), "; "))
+	}
+
+	return []byte(Node(program)), nil
+}
+
+// Node 는 AST 노드를 표준 형식의 소스 코드로 출력한다. *ast.Program 의 출력은 줄바꿈으로 끝난다.
+func Node(node ast.Node) string {
+	pr := &printer{}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		pr.program(node)
+	case ast.Statement:
+		pr.statement(node, nil, false)
+	case ast.Expression:
+		pr.expression(node, parser.LOWEST)
+	}
+
+	return pr.out.String()
+}
+
+type printer struct {
+	out   bytes.Buffer
+	depth int // 현재 들여쓰기 단계
+}
+
+func (pr *printer) write(s string) {
+	pr.out.WriteString(s)
+}
+
+// newline 은 줄을 바꾸고 현재 단계만큼 들여쓴다.
+func (pr *printer) newline() {
+	pr.out.WriteString("\n")
+	pr.out.WriteString(strings.Repeat(indent, pr.depth))
+}
+
+func (pr *printer) program(program *ast.Program) {
+	for i, stmt := range program.Statements {
+		var next ast.Statement
+		if i+1 < len(program.Statements) {
+			next = program.Statements[i+1]
+		}
+
+		text := Node(stmt)
+		multiline := strings.Contains(text, "\n")
+
+		// 여러 줄로 출력되는 문의 앞뒤에 빈 줄을 둔다
+		if i > 0 && (multiline || isMultiline(program.Statements[i-1])) {
+			pr.write("\n")
+		}
+
+		pr.statement(stmt, next, false)
+		pr.write("\n")
+	}
+}
+
+func isMultiline(stmt ast.Statement) bool {
+	return strings.Contains(Node(stmt), "\n")
+}
+
+// statement 는 문 하나를 출력한다. next 는 같은 블록에서 뒤따르는 문이고, inBlock 은 블록 안의 문인지 여부이다.
+func (pr *printer) statement(stmt ast.Statement, next ast.Statement, inBlock bool) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		pr.write("let ")
+		pr.write(stmt.Name.Value)
+		pr.write(" = ")
+		pr.expression(stmt.Value, parser.LOWEST)
+		pr.write(";")
+
+	case *ast.ReturnStatement:
+		pr.write("return ")
+		pr.expression(stmt.ReturnValue, parser.LOWEST)
+		pr.write(";")
+
+	case *ast.ExpressionStatement:
+		pr.expression(stmt.Expression, parser.LOWEST)
+		if needsSemicolon(stmt, next, inBlock) {
+			pr.write(";")
+		}
+
+	case *ast.BlockStatement:
+		pr.block(stmt)
+	}
+}
+
+// needsSemicolon 은 표현식 문 뒤에 세미콜론이 필요한지 판단한다.
+// 블록의 마지막 문은 블록의 값이므로 세미콜론 없이 출력한다.
+// if 표현식은 중괄호로 끝나지만 뒤따르는 문이 ( 나 [ 로 시작하면 호출이나 인덱스로 파싱되므로,
+// 다음 문이 없거나 키워드로 시작하는 경우에만 세미콜론을 생략한다.
+func needsSemicolon(stmt *ast.ExpressionStatement, next ast.Statement, inBlock bool) bool {
+	if next == nil && inBlock {
+		return false
+	}
+
+	if _, ok := stmt.Expression.(*ast.IfExpression); ok {
+		switch next.(type) {
+		case nil, *ast.LetStatement, *ast.ReturnStatement:
+			return false
+		}
+	}
+
+	return true
+}
+
+// block 은 중괄호로 감싼 블록을 여러 줄로 출력한다. 빈 블록은 {} 로 출력한다.
+func (pr *printer) block(block *ast.BlockStatement) {
+	if len(block.Statements) == 0 {
+		pr.write("{}")
+		return
+	}
+
+	pr.write("{")
+	pr.depth++
+	for i, stmt := range block.Statements {
+		var next ast.Statement
+		if i+1 < len(block.Statements) {
+			next = block.Statements[i+1]
+		}
+
+		pr.newline()
+		pr.statement(stmt, next, true)
+	}
+	pr.depth--
+	pr.newline()
+	pr.write("}")
+}
+
+// expression 은 표현식을 출력한다. outer 는 표현식을 감싸는 연산자의 우선순위로,
+// 표현식의 우선순위가 outer 보다 낮다면 괄호로 감싼다.
+func (pr *printer) expression(exp ast.Expression, outer int) {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		pr.write(exp.Value)
+
+	case *ast.IntegerLiteral:
+		pr.write(exp.Token.Literal)
+
+	case *ast.StringLiteral:
+		pr.write(`"` + exp.Value + `"`)
+
+	case *ast.Boolean:
+		pr.write(exp.Token.Literal)
+
+	case *ast.PrefixExpression:
+		pr.write(exp.Operator)
+		pr.expression(exp.Right, parser.PREFIX)
+
+	case *ast.InfixExpression:
+		precedence := parser.Precedence(token.TokenType(exp.Operator))
+		if precedence < outer {
+			pr.write("(")
+		}
+		// 중위 연산자는 왼쪽으로 결합하므로 오른쪽 피연산자는 같은 우선순위여도 괄호가 필요하다
+		pr.expression(exp.Left, precedence)
+		pr.write(" " + exp.Operator + " ")
+		pr.expression(exp.Right, precedence+1)
+		if precedence < outer {
+			pr.write(")")
+		}
+
+	case *ast.IfExpression:
+		pr.write("if (")
+		pr.expression(exp.Condition, parser.LOWEST)
+		pr.write(") ")
+		pr.block(exp.Consequence)
+		if exp.Alternative != nil {
+			pr.write(" else ")
+			pr.block(exp.Alternative)
+		}
+
+	case *ast.FunctionLiteral:
+		pr.write("fn(")
+		for i, param := range exp.Parameters {
+			if i > 0 {
+				pr.write(", ")
+			}
+			pr.write(param.Value)
+		}
+		pr.write(") ")
+		pr.block(exp.Body)
+
+	case *ast.CallExpression:
+		pr.operand(exp.Function)
+		pr.write("(")
+		pr.expressions(exp.Arguments)
+		pr.write(")")
+
+	case *ast.ArrayLiteral:
+		pr.write("[")
+		pr.expressions(exp.Elements)
+		pr.write("]")
+
+	case *ast.IndexExpression:
+		pr.operand(exp.Left)
+		pr.write("[")
+		pr.expression(exp.Index, parser.LOWEST)
+		pr.write("]")
+
+	case *ast.SliceExpression:
+		pr.operand(exp.Left)
+		pr.write("[")
+		if exp.Start != nil {
+			pr.expression(exp.Start, parser.LOWEST)
+		}
+		pr.write(":")
+		if exp.End != nil {
+			pr.expression(exp.End, parser.LOWEST)
+		}
+		if exp.Step != nil {
+			pr.write(":")
+			pr.expression(exp.Step, parser.LOWEST)
+		}
+		pr.write("]")
+
+	case *ast.HashLiteral:
+		pr.write("{")
+		for i, pair := range exp.Pairs {
+			if i > 0 {
+				pr.write(", ")
+			}
+			pr.expression(pair.Key, parser.LOWEST)
+			pr.write(": ")
+			pr.expression(pair.Value, parser.LOWEST)
+		}
+		pr.write("}")
+
+	case *ast.ImportExpression:
+		pr.write(`import "` + exp.Path.Value + `"`)
+	}
+}
+
+// operand 는 호출, 인덱스, 구간 표현식의 왼쪽 피연산자를 출력한다.
+// 전위, 중위 표현식은 괄호로 감싸야 호출이나 인덱스가 표현식 전체에 적용된다.
+func (pr *printer) operand(exp ast.Expression) {
+	switch exp.(type) {
+	case *ast.PrefixExpression, *ast.InfixExpression:
+		pr.write("(")
+		pr.expression(exp, parser.LOWEST)
+		pr.write(")")
+	default:
+		pr.expression(exp, parser.INDEX)
+	}
+}
+
+func (pr *printer) expressions(exps []ast.Expression) {
+	for i, exp := range exps {
+		if i > 0 {
+			pr.write(", ")
+		}
+		pr.expression(exp, parser.LOWEST)
+	}
+}
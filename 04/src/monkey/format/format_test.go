@@ -0,0 +1,122 @@
+package format
+
+import (
+	"flag"
+	"monkey/lexer"
+	"monkey/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestGolden 은 testdata 의 *.input 을 형식에 맞춘 결과가 *.golden 과 같고,
+// *.golden 을 다시 형식에 맞추어도 바뀌지 않는지 확인한다.
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden test inputs found")
+	}
+
+	for _, input := range inputs {
+		golden := strings.TrimSuffix(input, ".input") + ".golden"
+
+		src, err := os.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Source(src)
+		if err != nil {
+			t.Errorf("%s: %s", input, err)
+			continue
+		}
+
+		if *update {
+			if err := os.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s: wrong output.\nexpected:\n%s\ngot:\n%s", input, want, got)
+		}
+
+		again, err := Source(want)
+		if err != nil {
+			t.Errorf("%s: %s", golden, err)
+			continue
+		}
+		if string(again) != string(want) {
+			t.Errorf("%s: not idempotent.\nexpected:\n%s\ngot:\n%s", golden, want, again)
+		}
+	}
+}
+
+// TestPreservesMeaning 은 형식을 맞춘 소스 코드가 원래 소스 코드와 같은 AST 로 파싱되는지 확인한다.
+func TestPreservesMeaning(t *testing.T) {
+	tests := []string{
+		"1 + 2 * 3",
+		"(1 + 2) * 3",
+		"a - (b - c)",
+		"a - b - c",
+		"-(-a)",
+		"!-a",
+		"-a * b",
+		"(a * b)(c)",
+		"add(a, b)[1][2]",
+		"(a + b)[1:2]",
+		"a[::-1][0]",
+		"if (x) { y }; [1, 2]",
+		"if (x) { y } (1)",
+		"fn(x) { x }(1)",
+		`{"a": [1, 2], 1: fn() {}}`,
+		"let x = if (a) { b } else { c }; x",
+		`import "a.mk"`,
+	}
+
+	for _, input := range tests {
+		want := parse(t, input)
+
+		out, err := Source([]byte(input))
+		if err != nil {
+			t.Errorf("%q: %s", input, err)
+			continue
+		}
+		got := parse(t, string(out))
+
+		if got != want {
+			t.Errorf("%q: meaning changed. expected=%q, got=%q (formatted %q)",
+				input, want, got, out)
+		}
+	}
+}
+
+func TestSourceParseError(t *testing.T) {
+	_, err := Source([]byte("let = 5;"))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.HasPrefix(err.Error(), "parser errors: ") {
+		t.Errorf("wrong error message. got=%q", err.Error())
+	}
+}
+
+// parse 는 input 의 AST 를 String() 으로 반환한다
+func parse(t *testing.T, input string) string {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program.String()
+}
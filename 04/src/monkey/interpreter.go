@@ -0,0 +1,127 @@
+// Package monkey 는 Go 프로그램에 Monkey 인터프리터를 임베딩하기 위한 API 를 제공한다.
+//
+//	interp := monkey.New()
+//	interp.Set("limit", &object.Integer{Value: 10})
+//	result, err := interp.Eval("limit * 2")
+package monkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"strings"
+)
+
+// ParseError 는 소스 코드를 파싱하는 중에 발생한 에러들을 담는다.
+type ParseError struct {
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	return "parser errors: " + strings.Join(e.Errors, "; ")
+}
+
+// ErrPanic 은 평가 중에 panic 이 발생했을 때 반환되는 에러 객체의 Cause 이다.
+// 등록한 내장 함수가 panic 하더라도 호스트 프로세스가 죽지 않도록 EvalContext 가 panic 을 에러로 바꾼다.
+var ErrPanic = errors.New("panic during evaluation")
+
+// Interpreter 는 전역 바인딩과 내장 함수를 유지하면서 여러 번 소스 코드를 평가할 수 있는 인터프리터이다.
+// 한 번의 Eval 에서 let 으로 정의한 바인딩은 이후의 Eval 에서도 사용할 수 있다.
+//
+// Interpreter 의 메서드는 여러 고루틴에서 동시에 호출할 수 있다. 다만 동시에 실행되는 스크립트들이
+// 같은 전역 바인딩을 덮어쓰지 않도록 하려면, 전역 바인딩을 준비한 뒤 Freeze 를 호출하는 것이 좋다.
+type Interpreter struct {
+	// Options 는 Eval 에 적용할 실행 제한이다. 0 값이면 evaluator.Eval 과 같은 기본값을 사용한다.
+	// Modules 가 nil 이면 인터프리터의 모듈 로더를 사용하고, Builtins 는 인터프리터의 내장 함수 환경으로 바뀐다.
+	Options evaluator.Options
+
+	builtins *object.Environment // RegisterBuiltin 으로 등록한 내장 함수
+	globals  *object.Environment
+	// modules 는 Options.Modules 가 nil 일 때 사용하는 이 인터프리터만의 모듈 로더.
+	// 모듈은 builtins 를 바깥 환경으로 평가되므로 다른 인터프리터와 모듈 캐시를 공유하지 않는다
+	modules *evaluator.ModuleLoader
+}
+
+// New 는 새로운 전역 환경을 가진 Interpreter 를 만든다.
+func New() *Interpreter {
+	builtins := object.NewEnvironment()
+	return &Interpreter{
+		builtins: builtins,
+		globals:  object.NewEnclosedEnvironment(builtins),
+		modules:  evaluator.NewModuleLoader(evaluator.DefaultModuleLoader.Paths...),
+	}
+}
+
+// Eval 은 src 를 파싱하고 평가한 결과를 반환한다.
+// 파싱에 실패하면 *ParseError 를, 평가 중 에러가 발생하면 *object.Error 를 error 로 반환한다.
+func (i *Interpreter) Eval(src string) (object.Object, error) {
+	return i.EvalContext(context.Background(), src)
+}
+
+// EvalContext 는 Eval 과 같지만 ctx 가 취소되면 평가를 멈춘다.
+// 평가 중에 panic 이 발생하면 Cause 가 ErrPanic 인 *object.Error 를 반환한다.
+func (i *Interpreter) EvalContext(ctx context.Context, src string) (result object.Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &object.Error{Message: fmt.Sprintf("panic: %v", r), Cause: ErrPanic}
+		}
+	}()
+
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, &ParseError{Errors: p.Errors()}
+	}
+
+	env := i.globals
+	if env.Frozen() {
+		// 얼린 전역 환경 위에 실행마다 새로운 환경을 만들어 let 바인딩이 서로 섞이지 않게 한다
+		env = object.NewEnclosedEnvironment(i.globals)
+	}
+
+	opts := i.Options
+	if opts.Modules == nil {
+		opts.Modules = i.modules
+	}
+	opts.Builtins = i.builtins
+
+	result = evaluator.EvalContext(ctx, program, env, opts)
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, errObj
+	}
+
+	// let 문처럼 값을 만들어내지 않는 경우에는 NULL 을 반환한다
+	if result == nil {
+		return evaluator.NULL, nil
+	}
+
+	return result, nil
+}
+
+// Set 은 전역 환경에 name 이라는 이름으로 val 을 바인딩한다. Freeze 이후에 호출하면 panic 한다.
+func (i *Interpreter) Set(name string, val object.Object) {
+	i.globals.Set(name, val)
+}
+
+// Get 은 전역 환경에서 name 에 바인딩된 값을 찾는다.
+func (i *Interpreter) Get(name string) (object.Object, bool) {
+	return i.globals.Get(name)
+}
+
+// RegisterBuiltin 은 이 인터프리터에서만 사용할 수 있는 내장 함수를 등록한다.
+// 같은 이름의 기본 내장 함수가 있다면 등록한 함수가 우선한다. Freeze 이후에 호출하면 panic 한다.
+func (i *Interpreter) RegisterBuiltin(name string, fn object.BuiltinFunction) {
+	i.builtins.Set(name, &object.Builtin{Fn: fn})
+}
+
+// Freeze 는 전역 바인딩과 등록된 내장 함수를 읽기 전용으로 만든다.
+// 이후의 Eval 은 각각 얼린 전역 환경을 바깥 환경으로 갖는 새로운 환경에서 평가되므로,
+// 여러 고루틴에서 동시에 Eval 을 호출해도 let 바인딩이 서로 섞이지 않는다.
+func (i *Interpreter) Freeze() {
+	i.builtins.Freeze()
+	i.globals.Freeze()
+}
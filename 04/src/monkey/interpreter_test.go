@@ -0,0 +1,220 @@
+package monkey
+
+import (
+	"errors"
+	"fmt"
+	"monkey/evaluator"
+	"monkey/object"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestInterpreterEval(t *testing.T) {
+	interp := New()
+
+	result, err := interp.Eval("let add = fn(a, b) { a + b }; add(1, 2);")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testIntegerObject(t, result, 3)
+
+	// 이전 Eval 에서 정의한 바인딩을 계속 사용할 수 있다
+	result, err = interp.Eval("add(add(1, 2), 3)")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testIntegerObject(t, result, 6)
+
+	result, err = interp.Eval("let x = 1;")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != evaluator.NULL {
+		t.Errorf("result is not NULL. got=%T (%+v)", result, result)
+	}
+}
+
+func TestInterpreterErrors(t *testing.T) {
+	interp := New()
+
+	_, err := interp.Eval("let = 5;")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("err is not *ParseError. got=%T (%+v)", err, err)
+	}
+	if len(parseErr.Errors) == 0 {
+		t.Errorf("ParseError has no errors")
+	}
+
+	_, err = interp.Eval("5 + true")
+	var errObj *object.Error
+	if !errors.As(err, &errObj) {
+		t.Fatalf("err is not *object.Error. got=%T (%+v)", err, err)
+	}
+	if err.Error() != "type mismatch: INTEGER + BOOLEAN" {
+		t.Errorf("wrong error message. got=%q", err.Error())
+	}
+
+	_, err = interp.Eval("1 / 0")
+	if err == nil || err.Error() != "division by zero" {
+		t.Errorf("wrong error for division by zero. got=%v", err)
+	}
+}
+
+func TestInterpreterPanics(t *testing.T) {
+	// 등록한 내장 함수의 panic 은 호출한 쪽으로 전파되지 않고 에러로 반환된다
+	interp := New()
+	interp.RegisterBuiltin("explode", func(args ...object.Object) object.Object {
+		panic("boom")
+	})
+	fn, err := object.FromGo(func() int { panic("go boom") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.Set("go_explode", fn)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"explode()", "panic: boom"},
+		{"go_explode()", "panic: go boom"},
+	}
+	for _, tt := range tests {
+		_, err := interp.Eval(tt.input)
+		if !errors.Is(err, ErrPanic) || err.Error() != tt.expected {
+			t.Errorf("wrong error for %q. got=%v", tt.input, err)
+		}
+	}
+
+	// panic 이후에도 인터프리터를 계속 사용할 수 있다
+	result, err := interp.Eval("1 + 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testIntegerObject(t, result, 2)
+}
+
+func TestInterpreterModules(t *testing.T) {
+	// 모듈은 인터프리터에 등록한 내장 함수를 사용할 수 있고, 모듈 캐시는 인터프리터마다 따로 있다
+	module := filepath.Join(t.TempDir(), "host.mk")
+	if err := os.WriteFile(module, []byte("let v = host_fn();"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := `import "` + module + `"["v"]`
+
+	for _, value := range []int64{1, 2} {
+		interp := New()
+		interp.RegisterBuiltin("host_fn", func(args ...object.Object) object.Object {
+			return &object.Integer{Value: value}
+		})
+		result, err := interp.Eval(src)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		testIntegerObject(t, result, value)
+	}
+}
+
+func TestInterpreterSetGet(t *testing.T) {
+	interp := New()
+	interp.Set("limit", &object.Integer{Value: 10})
+
+	result, err := interp.Eval("let doubled = limit * 2; doubled + 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testIntegerObject(t, result, 21)
+
+	doubled, ok := interp.Get("doubled")
+	if !ok {
+		t.Fatalf("doubled is not bound")
+	}
+	testIntegerObject(t, doubled, 20)
+
+	if _, ok := interp.Get("missing"); ok {
+		t.Errorf("missing should not be bound")
+	}
+}
+
+func TestInterpreterRegisterBuiltin(t *testing.T) {
+	interp := New()
+	interp.RegisterBuiltin("triple", func(args ...object.Object) object.Object {
+		return &object.Integer{Value: args[0].(*object.Integer).Value * 3}
+	})
+	// 기본 내장 함수를 덮어쓸 수 있다
+	interp.RegisterBuiltin("len", func(args ...object.Object) object.Object {
+		return &object.Integer{Value: -1}
+	})
+
+	result, err := interp.Eval("triple(2) + len([1, 2])")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testIntegerObject(t, result, 5)
+
+	// 등록한 내장 함수는 다른 인터프리터에서 보이지 않는다
+	_, err = New().Eval("triple(2)")
+	if err == nil || err.Error() != "identifier not found: triple" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+}
+
+func TestInterpreterOptions(t *testing.T) {
+	interp := New()
+	interp.Options = evaluator.Options{MaxSteps: 100}
+
+	_, err := interp.Eval("let loop = fn() { loop() }; loop();")
+	if !errors.Is(err, evaluator.ErrMaxSteps) {
+		t.Errorf("err is not ErrMaxSteps. got=%v", err)
+	}
+}
+
+func TestInterpreterConcurrentEval(t *testing.T) {
+	interp := New()
+	interp.RegisterBuiltin("square", func(args ...object.Object) object.Object {
+		v := args[0].(*object.Integer).Value
+		return &object.Integer{Value: v * v}
+	})
+	if _, err := interp.Eval("let base = 10;"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	interp.Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src := fmt.Sprintf("let x = %d; base + square(x)", i)
+			result, err := interp.Eval(src)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			testIntegerObject(t, result, int64(10+i*i))
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := interp.Get("x"); ok {
+		t.Errorf("x leaked into frozen globals")
+	}
+}
+
+func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
+	result, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("object is not Integer. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%d, want=%d",
+			result.Value, expected)
+		return false
+	}
+
+	return true
+}
@@ -7,18 +7,42 @@ type Lexer struct {
 	position     int  // current position in input (points to current char)
 	readPosition int  // current reading position in input (after current char)
 	ch           byte // current char under examination
+	line         int  // 현재 문자의 줄 번호
+	column       int  // 현재 문자의 줄 안에서의 위치
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
-func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+// Tokenize 는 input 의 모든 토큰을 마지막 EOF 토큰까지 포함하여 반환한다.
+func Tokenize(input string) []token.Token {
+	l := New(input)
+
+	var tokens []token.Token
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
 
+// NextToken 은 다음 토큰을 읽고, 토큰이 시작하는 위치를 Pos 에 기록한다.
+func (l *Lexer) NextToken() token.Token {
 	l.skipWhitespace()
+	pos := token.Position{Line: l.line, Column: l.column}
+
+	tok := l.readToken()
+	tok.Pos = pos
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
 
 	switch l.ch {
 	case '=':
@@ -100,6 +124,14 @@ func (l *Lexer) skipWhitespace() {
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	if l.readPosition <= len(l.input) {
+		l.column++
+	}
+
 	if l.readPosition >= len(l.input) {
 		// 입력의 끝에 도달했을 때
 		l.ch = 0
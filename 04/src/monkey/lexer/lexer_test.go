@@ -30,6 +30,7 @@ if (5 < 10) {
 "foo bar"
 [1, 2];
 {"foo": "bar"}
+import "lib/math.mk"
 `
 
 	tests := []struct {
@@ -122,6 +123,8 @@ if (5 < 10) {
 		{token.COLON, ":"},
 		{token.STRING, "bar"},
 		{token.RBRACE, "}"},
+		{token.IMPORT, "import"},
+		{token.STRING, "lib/math.mk"},
 		{token.EOF, ""},
 	}
 
@@ -141,3 +144,41 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5;\n  x + \"ab\"\n\tfn"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"x", 1, 5},
+		{"=", 1, 7},
+		{"5", 1, 9},
+		{";", 1, 10},
+		{"x", 2, 3},
+		{"+", 2, 5},
+		{"ab", 2, 7},
+		{"fn", 3, 2},
+		{"", 3, 4},
+	}
+
+	tokens := Tokenize(input)
+	if len(tokens) != len(tests) {
+		t.Fatalf("wrong number of tokens. expected=%d, got=%d", len(tests), len(tokens))
+	}
+
+	for i, tt := range tests {
+		tok := tokens[i]
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos.Line != tt.expectedLine || tok.Pos.Column != tt.expectedColumn {
+			t.Errorf("tests[%d] - position wrong. expected=%d:%d, got=%s",
+				i, tt.expectedLine, tt.expectedColumn, tok.Pos)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+// Package lint 는 Monkey 프로그램을 실행하지 않고 흔한 실수를 찾아내는 정적 분석기를 제공한다.
+//
+// 분석은 평가기와 같은 스코프 규칙을 따른다. 함수 리터럴만 새로운 스코프를 만들고,
+// if 의 블록 안의 let 은 감싸고 있는 함수의 스코프에 바인딩된다. 함수의 본문은 호출될 때 평가되므로
+// 감싸고 있는 스코프에서 나중에 선언된 이름도 사용할 수 있다.
+package lint
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/testname"
+	"monkey/token"
+	"sort"
+	"strings"
+)
+
+// 규칙 ID
+const (
+	UnusedLet       = "unused-let"       // 사용되지 않는 let 바인딩
+	ShadowedBuiltin = "shadowed-builtin" // 내장함수의 이름을 가리는 바인딩
+	Undeclared      = "undeclared"       // 선언되지 않은 식별자의 사용
+	Unreachable     = "unreachable"      // return 뒤의 실행되지 않는 코드
+	NotCallable     = "not-callable"     // 함수가 아닌 값의 호출
+)
+
+// Rules 는 모든 규칙 ID 와 설명이다
+var Rules = map[string]string{
+	UnusedLet:       "let binding is never used",
+	ShadowedBuiltin: "binding shadows a builtin function",
+	Undeclared:      "identifier is not declared",
+	Unreachable:     "code after return is never executed",
+	NotCallable:     "called value is not a function",
+}
+
+// Diagnostic 분석기가 찾아낸 문제 하나
+type Diagnostic struct {
+	Pos     token.Position
+	Rule    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Pos, d.Message, d.Rule)
+}
+
+// Options 분석의 설정
+type Options struct {
+	// Disabled 는 보고하지 않을 규칙 ID 들이다
+	Disabled []string
+	// Globals 는 호스트 프로그램이 미리 정의한 이름들이다. 이 이름들은 선언된 것으로 취급한다
+	Globals []string
+	// Test 가 true 이면 program 을 테스트 파일로 분석한다. 최상위의 test_ 함수들은 테스트 러너가 호출하므로 사용된 것으로 본다
+	Test bool
+}
+
+// Lint 는 program 을 분석하여 찾아낸 문제들을 위치 순서대로 반환한다.
+// 최상위의 let 바인딩도 사용되지 않으면 보고하므로, 다른 파일에서 import 하는 모듈을 분석할 때는
+// unused-let 규칙을 끄는 것이 좋다. 이름이 _ 로 시작하는 바인딩은 사용되지 않아도 보고하지 않는다.
+func Lint(program *ast.Program, opts Options) []Diagnostic {
+	l := &linter{
+		disabled: map[string]bool{},
+		builtins: map[string]bool{},
+	}
+	for _, rule := range opts.Disabled {
+		l.disabled[rule] = true
+	}
+	for _, name := range evaluator.BuiltinNames() {
+		l.builtins[name] = true
+	}
+
+	globals := newScope(nil)
+	for _, name := range opts.Globals {
+		globals.names[name] = &binding{used: true}
+	}
+
+	top := newScope(globals)
+	if opts.Test {
+		l.tests = top
+	}
+	l.function(top, program.Statements)
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i].Pos, l.diagnostics[j].Pos
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.diagnostics
+}
+
+// Resolve 는 program 의 식별자들을 그 식별자가 가리키는 let 이나 함수 매개변수의 이름으로 매핑한다.
+// 선언하는 식별자는 자기 자신으로 매핑되며, 내장함수나 선언되지 않은 식별자는 포함하지 않는다.
+func Resolve(program *ast.Program) map[*ast.Identifier]*ast.Identifier {
+	l := &linter{
+		disabled: map[string]bool{},
+		builtins: map[string]bool{},
+		refs:     map[*ast.Identifier]*ast.Identifier{},
+	}
+	for rule := range Rules {
+		l.disabled[rule] = true
+	}
+
+	l.function(newScope(nil), program.Statements)
+	return l.refs
+}
+
+// binding 은 let 이나 함수의 매개변수로 선언된 이름이다
+type binding struct {
+	ident *ast.Identifier // 선언하는 식별자. 호스트가 정의한 이름이라면 nil
+	name  string
+	pos   token.Position
+	value ast.Expression // let 으로 바인딩된 값. 매개변수라면 nil
+	used  bool
+	isLet bool
+}
+
+type scope struct {
+	parent   *scope
+	names    map[string]*binding
+	bindings []*binding // 선언된 순서대로 저장한다. 같은 이름이 다시 선언되어도 이전 바인딩을 보고하기 위함
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, names: map[string]*binding{}}
+}
+
+func (s *scope) lookup(name string) (*binding, bool) {
+	for ; s != nil; s = s.parent {
+		if b, ok := s.names[name]; ok {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+type linter struct {
+	disabled    map[string]bool
+	builtins    map[string]bool
+	diagnostics []Diagnostic
+
+	// pending 은 현재 스코프의 분석이 끝난 뒤에 분석할 함수 본문들이다
+	pending []func()
+
+	// refs 가 nil 이 아니라면 식별자와 그 선언을 기록한다
+	refs map[*ast.Identifier]*ast.Identifier
+	// tests 는 테스트 파일의 최상위 스코프. 테스트 파일이 아니라면 nil 이다
+	tests *scope
+}
+
+func (l *linter) report(pos token.Position, rule, format string, a ...interface{}) {
+	if l.disabled[rule] {
+		return
+	}
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Pos:     pos,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// function 은 함수 본문이나 프로그램처럼 새로운 스코프를 만드는 문들을 분석한다.
+// 안쪽의 함수 본문들은 이 스코프의 모든 선언이 끝난 뒤에 분석한다.
+func (l *linter) function(s *scope, statements []ast.Statement) {
+	outer := l.pending
+	l.pending = nil
+
+	l.statements(s, statements)
+
+	for len(l.pending) > 0 {
+		fn := l.pending[0]
+		l.pending = l.pending[1:]
+		fn()
+	}
+	l.pending = outer
+
+	for _, b := range s.bindings {
+		if b.isLet && !b.used && !strings.HasPrefix(b.name, "_") && !(s == l.tests && isTestFunction(b)) {
+			l.report(b.pos, UnusedLet, "%s declared but not used", b.name)
+		}
+	}
+}
+
+// isTestFunction 은 b 가 테스트 러너가 호출하는 테스트 함수인지 반환한다
+func isTestFunction(b *binding) bool {
+	_, ok := b.value.(*ast.FunctionLiteral)
+	return ok && strings.HasPrefix(b.name, testname.FuncPrefix)
+}
+
+func (l *linter) declare(s *scope, name *ast.Identifier, value ast.Expression, isLet bool) {
+	if l.builtins[name.Value] {
+		l.report(name.Token.Pos, ShadowedBuiltin, "%s shadows the builtin function %s", name.Value, name.Value)
+	}
+
+	b := &binding{ident: name, name: name.Value, pos: name.Token.Pos, value: value, isLet: isLet}
+	if l.refs != nil {
+		l.refs[name] = name
+	}
+	s.names[name.Value] = b
+	s.bindings = append(s.bindings, b)
+}
+
+func (l *linter) statements(s *scope, statements []ast.Statement) {
+	// 실행되지 않는 문들도 선언과 사용을 기록하기 위해 분석하지만, 보고는 첫 번째 문에서만 한다
+	returned, reported := false, false
+	for _, stmt := range statements {
+		if returned && !reported {
+			l.report(stmt.Pos(), Unreachable, "unreachable code after return")
+			reported = true
+		}
+
+		l.statement(s, stmt)
+
+		if _, ok := stmt.(*ast.ReturnStatement); ok {
+			returned = true
+		}
+	}
+}
+
+func (l *linter) statement(s *scope, stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		// 값을 먼저 분석한 뒤에 이름을 선언한다. let x = x + 1 의 x 는 이전의 바인딩을 가리킨다
+		l.expression(s, stmt.Value)
+		l.declare(s, stmt.Name, stmt.Value, true)
+
+	case *ast.ReturnStatement:
+		l.expression(s, stmt.ReturnValue)
+
+	case *ast.ExpressionStatement:
+		l.expression(s, stmt.Expression)
+
+	case *ast.BlockStatement:
+		l.statements(s, stmt.Statements)
+	}
+}
+
+func (l *linter) expression(s *scope, exp ast.Expression) {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		l.use(s, exp)
+
+	case *ast.PrefixExpression:
+		l.expression(s, exp.Right)
+
+	case *ast.InfixExpression:
+		l.expression(s, exp.Left)
+		l.expression(s, exp.Right)
+
+	case *ast.IfExpression:
+		l.expression(s, exp.Condition)
+		l.statement(s, exp.Consequence)
+		if exp.Alternative != nil {
+			l.statement(s, exp.Alternative)
+		}
+
+	case *ast.FunctionLiteral:
+		l.pending = append(l.pending, func() {
+			inner := newScope(s)
+			for _, param := range exp.Parameters {
+				l.declare(inner, param, nil, false)
+			}
+			l.function(inner, exp.Body.Statements)
+		})
+
+	case *ast.CallExpression:
+		l.expression(s, exp.Function)
+		l.callee(s, exp.Function)
+		for _, arg := range exp.Arguments {
+			l.expression(s, arg)
+		}
+
+	case *ast.ArrayLiteral:
+		for _, el := range exp.Elements {
+			l.expression(s, el)
+		}
+
+	case *ast.IndexExpression:
+		l.expression(s, exp.Left)
+		l.expression(s, exp.Index)
+
+	case *ast.SliceExpression:
+		l.expression(s, exp.Left)
+		for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+			if bound != nil {
+				l.expression(s, bound)
+			}
+		}
+
+	case *ast.HashLiteral:
+		for _, pair := range exp.Pairs {
+			l.expression(s, pair.Key)
+			l.expression(s, pair.Value)
+		}
+	}
+}
+
+func (l *linter) use(s *scope, ident *ast.Identifier) {
+	if b, ok := s.lookup(ident.Value); ok {
+		b.used = true
+		if l.refs != nil && b.ident != nil {
+			l.refs[ident] = b.ident
+		}
+		return
+	}
+	if l.builtins[ident.Value] {
+		return
+	}
+	l.report(ident.Token.Pos, Undeclared, "undeclared identifier %s", ident.Value)
+}
+
+// callee 는 호출되는 표현식이 함수가 될 수 없는 값인지 확인한다.
+// 식별자라면 그 이름에 let 으로 바인딩된 값을 확인한다.
+func (l *linter) callee(s *scope, fn ast.Expression) {
+	target := fn
+	if ident, ok := fn.(*ast.Identifier); ok {
+		b, ok := s.lookup(ident.Value)
+		if !ok || b.value == nil {
+			return
+		}
+		target = b.value
+	}
+
+	if kind := valueKind(target); kind != "" {
+		l.report(fn.Pos(), NotCallable, "%s is %s, not a function", fn.String(), kind)
+	}
+}
+
+// valueKind 는 exp 가 평가되면 항상 함수가 아닌 값이 되는 경우 그 종류를 반환한다. 알 수 없다면 빈 문자열을 반환한다
+func valueKind(exp ast.Expression) string {
+	switch exp.(type) {
+	case *ast.IntegerLiteral:
+		return "an integer"
+	case *ast.StringLiteral:
+		return "a string"
+	case *ast.Boolean:
+		return "a boolean"
+	case *ast.ArrayLiteral:
+		return "an array"
+	case *ast.HashLiteral:
+		return "a hash"
+	case *ast.PrefixExpression, *ast.InfixExpression:
+		return "an operator result"
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package lint
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x = 5; puts(x);", nil},
+		{"let x = 5;", []string{"1:5: x declared but not used (unused-let)"}},
+		{"let _x = 5;", nil},
+		{"let x = 1; let x = x + 1; x", nil},
+		{"let x = 1; let x = 2; x", []string{"1:5: x declared but not used (unused-let)"}},
+		{"let f = fn(x) { let y = 1; x }; f(1)", []string{"1:21: y declared but not used (unused-let)"}},
+		{"let len = fn(x) { x }; len(1)", []string{"1:5: len shadows the builtin function len (shadowed-builtin)"}},
+		{"let f = fn(first) { first }; f(1)", []string{"1:12: first shadows the builtin function first (shadowed-builtin)"}},
+		{"puts(y)", []string{"1:6: undeclared identifier y (undeclared)"}},
+		{"y; let y = 1; y", []string{"1:1: undeclared identifier y (undeclared)"}},
+		{"let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(10)", nil},
+		{"let f = fn() { g() }; let g = fn() { 1 }; f()", nil},
+		{"let f = fn(x) { fn(y) { x + y + z } }; f(1)", []string{"1:33: undeclared identifier z (undeclared)"}},
+		{"if (true) { let a = 1 }; a", nil},
+		{"let f = fn() { return 1; puts(2); puts(3) }; f()", []string{"1:26: unreachable code after return (unreachable)"}},
+		{"let f = fn(x) { if (x) { return 1; } 2 }; f(true)", nil},
+		{"let f = fn() { return 1; let y = 2; y }; f()", []string{"1:26: unreachable code after return (unreachable)"}},
+		{"5(1)", []string{"1:1: 5 is an integer, not a function (not-callable)"}},
+		{`let s = "a"; s()`, []string{"1:14: s is a string, not a function (not-callable)"}},
+		{"let a = [1]; a[0](1)", nil},
+		{"let f = fn(g) { g(1) }; f(fn(x) { x })", nil},
+		{"let h = {}; let h = fn() { 1 }; h()", []string{"1:5: h declared but not used (unused-let)"}},
+		{
+			"let len = 5;\nlen(1)",
+			[]string{
+				"1:5: len shadows the builtin function len (shadowed-builtin)",
+				"2:1: len is an integer, not a function (not-callable)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		diagnostics := Lint(parse(t, tt.input), Options{})
+		checkDiagnostics(t, tt.input, diagnostics, tt.expected)
+	}
+}
+
+func TestLintOptions(t *testing.T) {
+	input := "let x = y; let len = 1;"
+
+	diagnostics := Lint(parse(t, input), Options{
+		Disabled: []string{UnusedLet, ShadowedBuiltin},
+		Globals:  []string{"y"},
+	})
+	checkDiagnostics(t, input, diagnostics, nil)
+
+	diagnostics = Lint(parse(t, input), Options{Disabled: []string{UnusedLet}})
+	checkDiagnostics(t, input, diagnostics, []string{
+		"1:9: undeclared identifier y (undeclared)",
+		"1:16: len shadows the builtin function len (shadowed-builtin)",
+	})
+}
+
+func TestLintTestFile(t *testing.T) {
+	input := "let test_add = fn() { 1 }; let test_value = 2; let f = fn() { let test_inner = fn() { 3 }; 4 }; f()"
+
+	diagnostics := Lint(parse(t, input), Options{Test: true})
+	checkDiagnostics(t, input, diagnostics, []string{
+		"1:32: test_value declared but not used (unused-let)",
+		"1:67: test_inner declared but not used (unused-let)",
+	})
+
+	diagnostics = Lint(parse(t, input), Options{})
+	checkDiagnostics(t, input, diagnostics, []string{
+		"1:5: test_add declared but not used (unused-let)",
+		"1:32: test_value declared but not used (unused-let)",
+		"1:67: test_inner declared but not used (unused-let)",
+	})
+}
+
+func checkDiagnostics(t *testing.T, input string, diagnostics []Diagnostic, expected []string) {
+	t.Helper()
+
+	if len(diagnostics) != len(expected) {
+		t.Errorf("wrong number of diagnostics for %q. expected=%q, got=%q",
+			input, expected, diagnostics)
+		return
+	}
+	for i, d := range diagnostics {
+		if d.String() != expected[i] {
+			t.Errorf("wrong diagnostic for %q. expected=%q, got=%q",
+				input, expected[i], d.String())
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func TestResolve(t *testing.T) {
+	input := "let x = 1; let f = fn(x, y) { x + y + len(z) }; let x = x + f(x, 2); x"
+
+	refs := Resolve(parse(t, input))
+
+	// 각 식별자의 위치를 그 식별자가 가리키는 선언의 위치로 매핑한다
+	expected := map[string]string{
+		"1:5":  "1:5",  // let x
+		"1:16": "1:16", // let f
+		"1:23": "1:23", // 매개변수 x
+		"1:26": "1:26", // 매개변수 y
+		"1:31": "1:23",
+		"1:35": "1:26",
+		"1:53": "1:53", // 두 번째 let x
+		"1:57": "1:5",
+		"1:61": "1:16",
+		"1:63": "1:5",
+		"1:70": "1:53",
+	}
+
+	actual := map[string]string{}
+	for ident, decl := range refs {
+		actual[ident.Token.Pos.String()] = decl.Token.Pos.String()
+	}
+
+	if len(actual) != len(expected) {
+		t.Errorf("wrong number of references. expected=%v, got=%v", expected, actual)
+	}
+	for use, decl := range expected {
+		if actual[use] != decl {
+			t.Errorf("identifier at %s resolved wrong. expected=%s, got=%q", use, decl, actual[use])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package lsp
+
+// builtinDoc 은 hover 와 completion 에 보여줄 내장함수의 설명이다
+type builtinDoc struct {
+	signature string
+	doc       string
+}
+
+// builtinDocs 는 evaluator 의 모든 내장함수에 대한 설명이다
+var builtinDocs = map[string]builtinDoc{
+	"len":   {"len(x)", "Returns the length of a string in bytes, or the number of elements of an array or hash."},
+	"puts":  {"puts(args...)", "Prints each argument on its own line and returns null."},
+	"first": {"first(arr)", "Returns the first element of an array, or null if it is empty."},
+	"last":  {"last(arr)", "Returns the last element of an array, or null if it is empty."},
+	"rest":  {"rest(arr)", "Returns a new array without the first element, or null if the array is empty."},
+	"push":  {"push(arr, x)", "Returns a new array with x appended to arr."},
+
+	"split":       {"split(s, sep)", "Splits s around each occurrence of sep and returns an array of strings."},
+	"join":        {"join(arr, sep)", "Concatenates an array of strings, placing sep between elements."},
+	"trim":        {"trim(s) / trim(s, cutset)", "Removes leading and trailing whitespace, or the characters in cutset."},
+	"contains":    {"contains(s, sub) / contains(arr, x)", "Reports whether s contains sub, or whether arr contains x."},
+	"index_of":    {"index_of(s, sub) / index_of(arr, x)", "Returns the index of the first occurrence, or -1 if absent."},
+	"replace":     {"replace(s, old, new)", "Returns s with every occurrence of old replaced by new."},
+	"upper":       {"upper(s)", "Returns s with all letters mapped to upper case."},
+	"lower":       {"lower(s)", "Returns s with all letters mapped to lower case."},
+	"starts_with": {"starts_with(s, prefix)", "Reports whether s begins with prefix."},
+	"ends_with":   {"ends_with(s, suffix)", "Reports whether s ends with suffix."},
+	"repeat":      {"repeat(s, n)", "Returns s repeated n times."},
+	"format":      {"format(f, args...)", "Formats args according to the Go fmt verbs in f."},
+	"chars":       {"chars(s)", "Splits s into an array of single-character strings."},
+
+	"map":     {"map(arr, fn)", "Returns a new array with fn applied to each element."},
+	"filter":  {"filter(arr, fn)", "Returns the elements for which fn returns true."},
+	"reduce":  {"reduce(arr, fn) / reduce(arr, fn, initial)", "Folds the array with fn(acc, x), starting from initial or the first element."},
+	"sort":    {"sort(arr) / sort(arr, less)", "Returns a sorted copy of an array of integers or strings, or ordered by less(a, b)."},
+	"reverse": {"reverse(x)", "Returns the elements of an array or the characters of a string in reverse order."},
+	"slice":   {"slice(x, start) / slice(x, start, end)", "Returns the elements of x in [start, end)."},
+	"concat":  {"concat(a, b, ...)", "Concatenates arrays or strings."},
+	"zip":     {"zip(a, b, ...)", "Returns an array of arrays grouping elements at the same index."},
+	"flatten": {"flatten(arr) / flatten(arr, depth)", "Flattens nested arrays by one level, or by depth levels."},
+	"range":   {"range(end) / range(start, end) / range(start, end, step)", "Returns an array of integers in [start, end)."},
+
+	"keys":   {"keys(h)", "Returns the keys of a hash in insertion order."},
+	"values": {"values(h)", "Returns the values of a hash in insertion order."},
+	"items":  {"items(h)", "Returns the [key, value] pairs of a hash in insertion order."},
+	"has":    {"has(h, key)", "Reports whether the hash contains key."},
+	"delete": {"delete(h, key)", "Returns a new hash without key."},
+	"merge":  {"merge(a, b, ...)", "Returns a new hash with the pairs of all hashes; later values win."},
+
+	"spawn":   {"spawn(fn, args...)", "Calls fn in a new task and returns the task."},
+	"await":   {"await(task)", "Waits for a task to finish and returns its result."},
+	"cancel":  {"cancel(task)", "Cancels a task and the tasks it spawned."},
+	"channel": {"channel() / channel(cap)", "Creates a channel with buffer capacity cap."},
+	"send":    {"send(ch, value)", "Sends value on the channel."},
+	"recv":    {"recv(ch)", "Receives a value from the channel, or null once it is closed and drained."},
+	"close":   {"close(ch)", "Closes the channel."},
+	"select":  {"select(cases) / select(cases, true)", "Runs the first ready channel operation and returns [index, value]; with true, returns [-1, null] instead of waiting."},
+
+	"assert":       {"assert(cond) / assert(cond, message)", "Fails the current test unless cond is truthy."},
+	"assert_eq":    {"assert_eq(actual, expected) / assert_eq(actual, expected, message)", "Fails the current test unless actual == expected, showing where the values differ."},
+	"assert_error": {"assert_error(fn) / assert_error(fn, substr)", "Calls fn and fails unless it ends with an error containing substr; returns the error message."},
+}
@@ -0,0 +1,196 @@
+package lsp
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/lint"
+	"monkey/parser"
+	"monkey/testname"
+	"monkey/token"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document 는 열려 있는 문서와 그 분석 결과이다. 문서가 바뀔 때마다 새로 만든다.
+type document struct {
+	uri     string
+	version int
+	text    string
+	lines   []string
+
+	program *ast.Program
+	errors  []parser.Error
+	// refs 는 식별자를 그 식별자가 가리키는 선언으로 매핑한다
+	refs map[*ast.Identifier]*ast.Identifier
+	// idents 는 모든 식별자를 소스 코드에 나온 순서대로 저장한다
+	idents []*ast.Identifier
+	// decls 는 선언하는 식별자를 그 식별자를 선언한 LetStatement 나 FunctionLiteral 로 매핑한다
+	decls map[*ast.Identifier]ast.Node
+}
+
+func newDocument(uri string, version int, text string) *document {
+	d := &document{
+		uri:     uri,
+		version: version,
+		text:    text,
+		lines:   strings.Split(text, "\n"),
+		decls:   map[*ast.Identifier]ast.Node{},
+	}
+
+	p := parser.New(lexer.New(text))
+	d.program = p.ParseProgram()
+	d.errors = p.ErrorList()
+	d.refs = lint.Resolve(d.program)
+
+	ast.Inspect(d.program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Identifier:
+			d.idents = append(d.idents, node)
+		case *ast.LetStatement:
+			d.decls[node.Name] = node
+		case *ast.FunctionLiteral:
+			for _, param := range node.Parameters {
+				d.decls[param] = node
+			}
+		}
+		return true
+	})
+
+	return d
+}
+
+// diagnostics 는 파싱 에러들을 반환한다. 파싱에 성공했다면 정적 분석의 결과를 경고로 반환한다
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, err := range d.errors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.tokenRange(err.Pos, 1),
+			Severity: SeverityError,
+			Source:   "monkey",
+			Message:  err.Message,
+		})
+	}
+	if len(d.errors) != 0 {
+		return diagnostics
+	}
+
+	opts := lint.Options{Test: strings.HasSuffix(d.uri, testname.FileSuffix)}
+	for _, diag := range lint.Lint(d.program, opts) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.tokenRange(diag.Pos, d.wordLength(diag.Pos)),
+			Severity: SeverityWarning,
+			Code:     diag.Rule,
+			Source:   "monkey-lint",
+			Message:  diag.Message,
+		})
+	}
+	return diagnostics
+}
+
+// identAt 은 pos 에 있는 식별자를 반환한다
+func (d *document) identAt(pos Position) (*ast.Identifier, bool) {
+	tp := d.tokenPosition(pos)
+	for _, ident := range d.idents {
+		start := ident.Token.Pos
+		if start.Line == tp.Line && start.Column <= tp.Column && tp.Column <= start.Column+len(ident.Value) {
+			return ident, true
+		}
+	}
+	return nil, false
+}
+
+// references 는 decl 로 선언된 이름을 가리키는 모든 식별자를 소스 코드에 나온 순서대로 반환한다
+func (d *document) references(decl *ast.Identifier, includeDeclaration bool) []*ast.Identifier {
+	var result []*ast.Identifier
+	for _, ident := range d.idents {
+		if d.refs[ident] != decl {
+			continue
+		}
+		if ident == decl && !includeDeclaration {
+			continue
+		}
+		result = append(result, ident)
+	}
+	return result
+}
+
+func (d *document) identRange(ident *ast.Identifier) Range {
+	return d.tokenRange(ident.Token.Pos, len(ident.Value))
+}
+
+// tokenRange 는 pos 에서 시작하는 length 바이트의 범위를 반환한다
+func (d *document) tokenRange(pos token.Position, length int) Range {
+	start := d.lspPosition(pos)
+	end := d.lspPosition(token.Position{Line: pos.Line, Column: pos.Column + length})
+	return Range{Start: start, End: end}
+}
+
+// wordLength 는 pos 에서 시작하는 식별자나 키워드의 길이를 반환한다. 없다면 1 을 반환한다
+func (d *document) wordLength(pos token.Position) int {
+	if pos.Line < 1 || pos.Line > len(d.lines) {
+		return 1
+	}
+	line := d.lines[pos.Line-1]
+	n := 0
+	for i := pos.Column - 1; i >= 0 && i < len(line) && isWordByte(line[i]); i++ {
+		n++
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
+
+func isWordByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b == '_' || '0' <= b && b <= '9'
+}
+
+// lspPosition 은 1 부터 시작하는 바이트 단위의 위치를 0 부터 시작하는 UTF-16 단위의 위치로 바꾼다
+func (d *document) lspPosition(pos token.Position) Position {
+	if pos.Line < 1 {
+		return Position{}
+	}
+	if pos.Line > len(d.lines) {
+		return Position{Line: pos.Line - 1}
+	}
+
+	line := d.lines[pos.Line-1]
+	offset := pos.Column - 1
+	if offset > len(line) {
+		offset = len(line)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	character := 0
+	for _, r := range line[:offset] {
+		character += utf16.RuneLen(r)
+	}
+	return Position{Line: pos.Line - 1, Character: character}
+}
+
+// tokenPosition 은 lspPosition 의 역변환이다
+func (d *document) tokenPosition(pos Position) token.Position {
+	if pos.Line < 0 || pos.Line >= len(d.lines) {
+		return token.Position{Line: pos.Line + 1, Column: pos.Character + 1}
+	}
+
+	line := d.lines[pos.Line]
+	offset, character := 0, 0
+	for offset < len(line) && character < pos.Character {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		character += utf16.RuneLen(r)
+		offset += size
+	}
+	return token.Position{Line: pos.Line + 1, Column: offset + 1}
+}
+
+// fullRange 는 문서 전체의 범위를 반환한다
+func (d *document) fullRange() Range {
+	last := len(d.lines)
+	end := d.lspPosition(token.Position{Line: last, Column: len(d.lines[last-1]) + 1})
+	return Range{End: end}
+}
@@ -0,0 +1,380 @@
+// Package lsp 는 Monkey 소스 코드를 위한 Language Server Protocol 서버를 제공한다.
+//
+// 서버는 표준 입출력 같은 스트림 위에서 JSON-RPC 메시지를 주고받으며 다음 기능을 지원한다.
+//   - 파싱 에러와 정적 분석 결과의 진단
+//   - let 바인딩과 함수 매개변수의 정의로 이동, 참조 찾기
+//   - 내장함수의 설명을 보여주는 hover
+//   - 문서의 심볼 목록
+//   - 내장함수와 키워드의 자동 완성
+//   - 문서 전체의 형식 맞추기
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/format"
+	"monkey/token"
+	"strings"
+)
+
+// Server 는 하나의 클라이언트와 연결된 언어 서버이다. 요청은 받은 순서대로 하나씩 처리한다.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	docs     map[string]*document
+	shutdown bool
+}
+
+// NewServer 는 r 에서 요청을 읽고 w 에 응답을 쓰는 서버를 만든다.
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(r),
+		out:  w,
+		docs: map[string]*document{},
+	}
+}
+
+// Serve 는 exit 알림을 받거나 입력이 끝날 때까지 요청을 처리한다.
+// shutdown 요청 뒤에 exit 알림을 받았다면 nil 을, 그렇지 않다면 에러를 반환한다.
+func (s *Server) Serve() error {
+	for {
+		msg, err := readMessage(s.in)
+		if err == io.EOF {
+			return errors.New("lsp: connection closed before exit")
+		}
+		var rpcErr *responseError
+		if errors.As(err, &rpcErr) {
+			if err := s.reply(nil, nil, rpcErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("lsp: exit without shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(msg)
+		if msg.ID == nil {
+			// 알림에는 응답하지 않는다
+			continue
+		}
+		if err := s.reply(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rpcErr *responseError) error {
+	msg := &message{ID: id, Error: rpcErr}
+	if id == nil {
+		null := json.RawMessage("null")
+		msg.ID = &null
+	}
+	if rpcErr == nil {
+		body, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		msg.Result = body
+	}
+	return writeMessage(s.out, msg)
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return writeMessage(s.out, &message{Method: method, Params: body})
+}
+
+// handle 은 요청이나 알림 하나를 처리한다. 알림의 결과는 버려진다
+func (s *Server) handle(msg *message) (interface{}, *responseError) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc := params.TextDocument
+		return nil, s.update(newDocument(doc.URI, doc.Version, doc.Text))
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(newDocument(params.TextDocument.URI, params.TextDocument.Version, text))
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		// 닫힌 문서의 진단을 지운다
+		return nil, s.publish(&PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params)
+
+	case "textDocument/references":
+		var params ReferenceParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.references(params)
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params)
+
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.documentSymbol(params)
+
+	case "textDocument/completion":
+		return completion(), nil
+
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.formatting(params)
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+}
+
+func decode(params json.RawMessage, v interface{}) *responseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) initialize() InitializeResult {
+	var caps ServerCapabilities
+	caps.TextDocumentSync = 1
+	caps.HoverProvider = true
+	caps.DefinitionProvider = true
+	caps.ReferencesProvider = true
+	caps.DocumentSymbolProvider = true
+	caps.DocumentFormattingProvider = true
+	return InitializeResult{Capabilities: caps, ServerInfo: ServerInfo{Name: "monkey-lsp"}}
+}
+
+// update 는 문서를 새로운 분석 결과로 바꾸고 진단을 보낸다
+func (s *Server) update(doc *document) *responseError {
+	s.docs[doc.uri] = doc
+	return s.publish(&PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     doc.version,
+		Diagnostics: doc.diagnostics(),
+	})
+}
+
+func (s *Server) publish(params *PublishDiagnosticsParams) *responseError {
+	if err := s.notify("textDocument/publishDiagnostics", params); err != nil {
+		return &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) document(uri string) (*document, *responseError) {
+	doc, ok := s.docs[uri]
+	if !ok {
+		return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("document not open: %s", uri)}
+	}
+	return doc, nil
+}
+
+// declarationAt 은 pos 에 있는 식별자가 가리키는 선언을 반환한다
+func (s *Server) declarationAt(uri string, pos Position) (*document, *ast.Identifier, *responseError) {
+	doc, err := s.document(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	ident, ok := doc.identAt(pos)
+	if !ok {
+		return doc, nil, nil
+	}
+	return doc, doc.refs[ident], nil
+}
+
+func (s *Server) definition(params TextDocumentPositionParams) (interface{}, *responseError) {
+	doc, decl, err := s.declarationAt(params.TextDocument.URI, params.Position)
+	if err != nil || decl == nil {
+		return nil, err
+	}
+	return []Location{{URI: doc.uri, Range: doc.identRange(decl)}}, nil
+}
+
+func (s *Server) references(params ReferenceParams) (interface{}, *responseError) {
+	doc, decl, err := s.declarationAt(params.TextDocument.URI, params.Position)
+	if err != nil || decl == nil {
+		return nil, err
+	}
+
+	locations := []Location{}
+	for _, ident := range doc.references(decl, params.Context.IncludeDeclaration) {
+		locations = append(locations, Location{URI: doc.uri, Range: doc.identRange(ident)})
+	}
+	return locations, nil
+}
+
+func (s *Server) hover(params TextDocumentPositionParams) (interface{}, *responseError) {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ident, ok := doc.identAt(params.Position)
+	if !ok {
+		return nil, nil
+	}
+
+	var text string
+	if decl, ok := doc.refs[ident]; ok {
+		switch node := doc.decls[decl].(type) {
+		case *ast.LetStatement:
+			text = "let " + decl.Value
+			if fn, ok := node.Value.(*ast.FunctionLiteral); ok {
+				text += " = " + signature(fn)
+			}
+		case *ast.FunctionLiteral:
+			text = "parameter " + decl.Value + " of " + signature(node)
+		}
+		text = "```monkey\n" + text + "\n```"
+	} else if builtin, ok := builtinDocs[ident.Value]; ok {
+		text = "```monkey\n" + builtin.signature + "\n```\n\n" + builtin.doc
+	} else {
+		return nil, nil
+	}
+
+	return Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: text},
+		Range:    doc.identRange(ident),
+	}, nil
+}
+
+// signature 는 함수 리터럴의 본문을 제외한 fn(a, b) 형태를 반환한다
+func signature(fn *ast.FunctionLiteral) string {
+	params := make([]string, len(fn.Parameters))
+	for i, p := range fn.Parameters {
+		params[i] = p.Value
+	}
+	return "fn(" + strings.Join(params, ", ") + ")"
+}
+
+func (s *Server) documentSymbol(params DocumentSymbolParams) (interface{}, *responseError) {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	return doc.symbols(doc.program.Statements), nil
+}
+
+// symbols 는 문들에서 let 바인딩의 심볼들을 찾는다. 함수를 바인딩한 심볼은 함수 본문의 심볼들을 자식으로 가진다.
+// if 의 블록 안의 let 은 감싸고 있는 스코프에 바인딩되므로 같은 단계의 심볼로 취급한다.
+func (d *document) symbols(statements []ast.Statement) []DocumentSymbol {
+	result := []DocumentSymbol{}
+	for _, stmt := range statements {
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			symbol := DocumentSymbol{
+				Name:           stmt.Name.Value,
+				Kind:           SymbolKindVariable,
+				SelectionRange: d.identRange(stmt.Name),
+			}
+			symbol.Range = Range{Start: d.lspPosition(stmt.Token.Pos), End: symbol.SelectionRange.End}
+			if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+				symbol.Kind = SymbolKindFunction
+				symbol.Detail = signature(fn)
+				symbol.Children = d.symbols(fn.Body.Statements)
+			}
+			result = append(result, symbol)
+
+		case *ast.ExpressionStatement:
+			if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+				result = append(result, d.symbols(ifExp.Consequence.Statements)...)
+				if ifExp.Alternative != nil {
+					result = append(result, d.symbols(ifExp.Alternative.Statements)...)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func completion() []CompletionItem {
+	items := []CompletionItem{}
+	for _, name := range evaluator.BuiltinNames() {
+		doc := builtinDocs[name]
+		items = append(items, CompletionItem{
+			Label:         name,
+			Kind:          CompletionKindFunction,
+			Detail:        doc.signature,
+			Documentation: doc.doc,
+		})
+	}
+	for _, word := range token.Keywords() {
+		items = append(items, CompletionItem{Label: word, Kind: CompletionKindKeyword})
+	}
+	return items
+}
+
+func (s *Server) formatting(params DocumentFormattingParams) (interface{}, *responseError) {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	out, fmtErr := format.Source([]byte(doc.text))
+	if fmtErr != nil {
+		return nil, &responseError{Code: codeInternalError, Message: fmtErr.Error()}
+	}
+	if string(out) == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{Range: doc.fullRange(), NewText: string(out)}}, nil
+}
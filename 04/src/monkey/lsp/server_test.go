@@ -0,0 +1,429 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"monkey/evaluator"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// client 는 테스트를 위해 같은 프로세스에서 서버와 파이프로 연결된 클라이언트이다
+type client struct {
+	t      *testing.T
+	w      io.WriteCloser
+	r      *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{t: t, w: clientOut, r: bufio.NewReader(clientIn), done: make(chan error, 1)}
+	go func() {
+		err := NewServer(serverIn, serverOut).Serve()
+		serverOut.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+func (c *client) send(msg *message) {
+	c.t.Helper()
+	if err := writeMessage(c.w, msg); err != nil {
+		c.t.Fatalf("writing message: %s", err)
+	}
+}
+
+// notify 는 알림을 보낸다
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	body, _ := json.Marshal(params)
+	c.send(&message{Method: method, Params: body})
+}
+
+// call 은 요청을 보내고 응답의 결과를 result 에 디코딩한다. 응답이 에러라면 그 에러를 반환한다
+func (c *client) call(method string, params interface{}, result interface{}) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	body, _ := json.Marshal(params)
+	c.send(&message{ID: &id, Method: method, Params: body})
+
+	for {
+		msg, err := readMessage(c.r)
+		if err != nil {
+			c.t.Fatalf("reading response to %s: %s", method, err)
+		}
+		if msg.ID == nil {
+			// 응답을 기다리는 동안 받은 알림은 무시한다
+			continue
+		}
+		if string(*msg.ID) != string(id) {
+			c.t.Fatalf("response id wrong. expected=%s, got=%s", id, *msg.ID)
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("decoding result of %s: %s", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// diagnostics 는 알림을 기다려 uri 에 대해 가장 마지막으로 받은 진단을 반환한다
+func (c *client) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	for {
+		msg, err := readMessage(c.r)
+		if err != nil {
+			c.t.Fatalf("reading diagnostics: %s", err)
+		}
+		if msg.Method != "textDocument/publishDiagnostics" {
+			c.t.Fatalf("expected publishDiagnostics, got %q", msg.Method)
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+}
+
+func (c *client) open(uri, text string) []Diagnostic {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "monkey", Version: 1, Text: text},
+	})
+	return c.diagnostics(uri)
+}
+
+func (c *client) close() {
+	c.t.Helper()
+	if err := c.call("shutdown", nil, nil); err != nil {
+		c.t.Fatalf("shutdown failed: %s", err)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		c.t.Errorf("server returned error: %s", err)
+	}
+}
+
+func position(uri string, line, character int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: line, Character: character},
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	c := newClient(t)
+
+	var result InitializeResult
+	if err := c.call("initialize", map[string]interface{}{"capabilities": map[string]interface{}{}}, &result); err != nil {
+		t.Fatalf("initialize failed: %s", err)
+	}
+	c.notify("initialized", map[string]interface{}{})
+
+	caps := result.Capabilities
+	if caps.TextDocumentSync != 1 || !caps.HoverProvider || !caps.DefinitionProvider ||
+		!caps.ReferencesProvider || !caps.DocumentSymbolProvider || !caps.DocumentFormattingProvider {
+		t.Errorf("wrong capabilities. got=%+v", caps)
+	}
+
+	if err := c.call("unknown/method", nil, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("expected method not found error, got=%v", err)
+	}
+
+	c.close()
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///test.mk"
+
+	diagnostics := c.open(uri, "let x = 5;\nlet = 10;")
+	if len(diagnostics) == 0 {
+		t.Fatal("expected parser diagnostics, got none")
+	}
+	first := diagnostics[0]
+	if first.Severity != SeverityError || first.Message != "expected next token to be IDENT, got = instead" {
+		t.Errorf("wrong diagnostic. got=%+v", first)
+	}
+	if first.Range.Start != (Position{Line: 1, Character: 4}) {
+		t.Errorf("wrong diagnostic position. got=%+v", first.Range.Start)
+	}
+
+	// 파싱에 성공하면 정적 분석의 결과를 경고로 보낸다
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "let x = 5;\nputs(y);"}},
+	})
+	diagnostics = c.diagnostics(uri)
+	expected := []struct {
+		code  string
+		start Position
+		end   Position
+	}{
+		{"unused-let", Position{Line: 0, Character: 4}, Position{Line: 0, Character: 5}},
+		{"undeclared", Position{Line: 1, Character: 5}, Position{Line: 1, Character: 6}},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("wrong number of diagnostics. expected=%d, got=%+v", len(expected), diagnostics)
+	}
+	for i, tt := range expected {
+		d := diagnostics[i]
+		if d.Severity != SeverityWarning || d.Code != tt.code || d.Range.Start != tt.start || d.Range.End != tt.end {
+			t.Errorf("diagnostics[%d] wrong. expected=%+v, got=%+v", i, tt, d)
+		}
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	if diagnostics := c.diagnostics(uri); len(diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared, got=%+v", diagnostics)
+	}
+
+	c.close()
+}
+
+const source = `let add = fn(a, b) {
+  a + b
+};
+let total = add(1, 2);
+puts(add(total, 3));
+`
+
+func TestDefinitionAndReferences(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///refs.mk"
+	c.open(uri, source)
+
+	tests := []struct {
+		line, character int
+		expected        Range
+	}{
+		// add 를 호출하는 곳에서 let add 로 이동한다
+		{3, 13, Range{Start: Position{0, 4}, End: Position{0, 7}}},
+		{4, 6, Range{Start: Position{0, 4}, End: Position{0, 7}}},
+		// 함수 본문의 a 에서 매개변수 a 로 이동한다
+		{1, 2, Range{Start: Position{0, 13}, End: Position{0, 14}}},
+		// 식별자의 끝에서도 찾는다
+		{4, 14, Range{Start: Position{3, 4}, End: Position{3, 9}}},
+	}
+
+	for _, tt := range tests {
+		var locations []Location
+		if err := c.call("textDocument/definition", position(uri, tt.line, tt.character), &locations); err != nil {
+			t.Fatalf("definition failed: %s", err)
+		}
+		if len(locations) != 1 || locations[0].URI != uri || locations[0].Range != tt.expected {
+			t.Errorf("definition at %d:%d wrong. expected=%+v, got=%+v",
+				tt.line, tt.character, tt.expected, locations)
+		}
+	}
+
+	// 내장함수는 정의가 없다
+	var locations []Location
+	if err := c.call("textDocument/definition", position(uri, 4, 1), &locations); err != nil {
+		t.Fatalf("definition failed: %s", err)
+	}
+	if locations != nil {
+		t.Errorf("expected no definition for builtin, got=%+v", locations)
+	}
+
+	params := ReferenceParams{TextDocumentPositionParams: position(uri, 0, 5)}
+	params.Context.IncludeDeclaration = true
+	if err := c.call("textDocument/references", params, &locations); err != nil {
+		t.Fatalf("references failed: %s", err)
+	}
+	expected := []Position{{0, 4}, {3, 12}, {4, 5}}
+	if len(locations) != len(expected) {
+		t.Fatalf("wrong number of references. expected=%d, got=%+v", len(expected), locations)
+	}
+	for i, pos := range expected {
+		if locations[i].Range.Start != pos {
+			t.Errorf("references[%d] wrong. expected=%+v, got=%+v", i, pos, locations[i].Range.Start)
+		}
+	}
+
+	params.Context.IncludeDeclaration = false
+	if err := c.call("textDocument/references", params, &locations); err != nil {
+		t.Fatalf("references failed: %s", err)
+	}
+	if len(locations) != 2 {
+		t.Errorf("expected 2 references without declaration, got=%+v", locations)
+	}
+
+	c.close()
+}
+
+func TestHover(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///hover.mk"
+	c.open(uri, source)
+
+	tests := []struct {
+		line, character int
+		expected        string
+	}{
+		{4, 1, "```monkey\nputs(args...)\n```\n\nPrints each argument on its own line and returns null."},
+		{3, 14, "```monkey\nlet add = fn(a, b)\n```"},
+		{1, 6, "```monkey\nparameter b of fn(a, b)\n```"},
+		{4, 10, "```monkey\nlet total\n```"},
+	}
+
+	for _, tt := range tests {
+		var hover *Hover
+		if err := c.call("textDocument/hover", position(uri, tt.line, tt.character), &hover); err != nil {
+			t.Fatalf("hover failed: %s", err)
+		}
+		if hover == nil {
+			t.Errorf("no hover at %d:%d", tt.line, tt.character)
+			continue
+		}
+		if hover.Contents.Kind != "markdown" || hover.Contents.Value != tt.expected {
+			t.Errorf("hover at %d:%d wrong. expected=%q, got=%q",
+				tt.line, tt.character, tt.expected, hover.Contents.Value)
+		}
+	}
+
+	// 식별자가 아닌 곳에서는 hover 가 없다
+	var hover *Hover
+	if err := c.call("textDocument/hover", position(uri, 3, 17), &hover); err != nil {
+		t.Fatalf("hover failed: %s", err)
+	}
+	if hover != nil {
+		t.Errorf("expected no hover, got=%+v", hover)
+	}
+
+	c.close()
+}
+
+func TestDocumentSymbol(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///symbols.mk"
+	c.open(uri, "let f = fn(x) {\n  let y = x;\n  if (y) { let z = 1; z }\n};\nlet n = f(1);")
+
+	var symbols []DocumentSymbol
+	if err := c.call("textDocument/documentSymbol",
+		DocumentSymbolParams{TextDocument: TextDocumentIdentifier{URI: uri}}, &symbols); err != nil {
+		t.Fatalf("documentSymbol failed: %s", err)
+	}
+
+	var describe func(symbols []DocumentSymbol) string
+	describe = func(symbols []DocumentSymbol) string {
+		var parts []string
+		for _, s := range symbols {
+			part := s.Name + ":" + strconv.Itoa(s.Kind)
+			if len(s.Children) > 0 {
+				part += "{" + describe(s.Children) + "}"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	expected := "f:12{y:13 z:13} n:13"
+	if describe(symbols) != expected {
+		t.Errorf("wrong symbols. expected=%q, got=%q", expected, describe(symbols))
+	}
+	if symbols[0].Detail != "fn(x)" || symbols[0].SelectionRange.Start != (Position{0, 4}) {
+		t.Errorf("wrong symbol detail. got=%+v", symbols[0])
+	}
+
+	c.close()
+}
+
+func TestCompletion(t *testing.T) {
+	c := newClient(t)
+
+	var items []CompletionItem
+	if err := c.call("textDocument/completion", position("file:///any.mk", 0, 0), &items); err != nil {
+		t.Fatalf("completion failed: %s", err)
+	}
+
+	labels := map[string]int{}
+	for _, item := range items {
+		labels[item.Label] = item.Kind
+	}
+	for _, name := range []string{"len", "map", "spawn"} {
+		if labels[name] != CompletionKindFunction {
+			t.Errorf("missing builtin completion %q", name)
+		}
+	}
+	for _, word := range []string{"fn", "let", "if", "else", "return", "import"} {
+		if labels[word] != CompletionKindKeyword {
+			t.Errorf("missing keyword completion %q", word)
+		}
+	}
+
+	c.close()
+}
+
+func TestFormatting(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///fmt.mk"
+	c.open(uri, "let x=fn(a){a*2};\nx(1)")
+
+	params := DocumentFormattingParams{TextDocument: TextDocumentIdentifier{URI: uri}}
+	var edits []TextEdit
+	if err := c.call("textDocument/formatting", params, &edits); err != nil {
+		t.Fatalf("formatting failed: %s", err)
+	}
+
+	expected := "let x = fn(a) {\n  a * 2\n};\n\nx(1);\n"
+	if len(edits) != 1 || edits[0].NewText != expected {
+		t.Fatalf("wrong edits. got=%+v", edits)
+	}
+	if edits[0].Range != (Range{Start: Position{0, 0}, End: Position{1, 4}}) {
+		t.Errorf("wrong edit range. got=%+v", edits[0].Range)
+	}
+
+	c.close()
+}
+
+func TestUTF16Positions(t *testing.T) {
+	d := newDocument("file:///utf16.mk", 1, "let s = \"한😀\"; let t = s;")
+
+	// "한" 은 UTF-8 로 3 바이트, UTF-16 으로 1 단위이고 "😀" 는 4 바이트, 2 단위이다
+	ident := d.idents[len(d.idents)-1]
+	r := d.identRange(ident)
+	if r.Start != (Position{0, 23}) {
+		t.Errorf("wrong start. got=%+v", r.Start)
+	}
+
+	found, ok := d.identAt(Position{0, 23})
+	if !ok || found != ident {
+		t.Errorf("identAt did not find s")
+	}
+}
+
+func TestBuiltinDocs(t *testing.T) {
+	for _, name := range evaluator.BuiltinNames() {
+		if _, ok := builtinDocs[name]; !ok {
+			t.Errorf("missing documentation for builtin %q", name)
+		}
+	}
+	if len(builtinDocs) != len(evaluator.BuiltinNames()) {
+		t.Errorf("documentation for unknown builtins. got=%d, want=%d",
+			len(builtinDocs), len(evaluator.BuiltinNames()))
+	}
+}
+
+func TestContentLengthLimit(t *testing.T) {
+	// 최대 크기를 넘는 Content-Length 는 본문을 할당하기 전에 거부한다
+	r := bufio.NewReader(strings.NewReader("Content-Length: 1099511627776\r\n\r\n{}"))
+	_, err := readMessage(r)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Errorf("expected Content-Length error. got=%v", err)
+	}
+}
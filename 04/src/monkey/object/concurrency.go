@@ -0,0 +1,381 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrChannelClosed 는 닫힌 채널에 값을 보내거나 채널을 두 번 닫았을 때 반환된다
+	ErrChannelClosed = errors.New("channel is closed")
+	// ErrCanceled 는 채널 연산을 기다리는 도중 cancel 채널이 닫혔을 때 반환된다
+	ErrCanceled = errors.New("operation canceled")
+	// ErrDeadlock 은 기다리는 연산을 끝내줄 수 있는 고루틴이 더 이상 없을 때 반환된다
+	ErrDeadlock = errors.New("deadlock")
+)
+
+// mu 는 모든 채널과 Scheduler 의 상태를 보호한다.
+// 값을 주고받는 일과 기다리는 연산을 세는 일이 어긋나지 않도록, 그리고 여러 채널을 함께 기다리는 select 가
+// 잠금의 순서를 신경 쓰지 않도록 하나의 잠금을 사용한다.
+var mu sync.Mutex
+
+// Scheduler 는 한 번의 평가에서 실행 중인 고루틴과 기다리는 연산을 세어 교착 상태를 찾는다.
+// 평가를 시작한 고루틴과 실행 중인 Task 가 모두 교착 상태를 확인하는 연산에서 기다리고 있다면
+// 누구도 그 연산들을 끝내줄 수 없으므로 ErrDeadlock 으로 깨운다.
+// 교착 상태는 평가마다 따로 판단하므로 다른 평가만 끝내줄 수 있는 연산은 교착 상태로 볼 수 있다.
+type Scheduler struct {
+	running int                  // 평가를 시작한 고루틴과 실행 중인 Task 의 수
+	blocked map[*waiter]struct{} // 교착 상태를 확인하며 기다리는 연산들
+}
+
+// NewScheduler 는 한 번의 평가를 위한 Scheduler 를 만든다.
+func NewScheduler() *Scheduler {
+	return &Scheduler{blocked: map[*waiter]struct{}{}}
+}
+
+// Enter 는 지금의 고루틴이 평가를 시작함을 알리고, Exit 는 평가를 마쳤음을 알린다.
+func (sc *Scheduler) Enter() {
+	mu.Lock()
+	defer mu.Unlock()
+	sc.running++
+}
+
+func (sc *Scheduler) Exit() {
+	mu.Lock()
+	defer mu.Unlock()
+	sc.running--
+	sc.checkDeadlock()
+}
+
+// Wait 는 채널 연산이나 Task 를 기다리는 동안 기다리기를 그만두는 조건이다.
+type Wait struct {
+	// Cancel 이 닫히면 기다리기를 그만두고 ErrCanceled 를 반환한다. nil 이면 취소되지 않는다
+	Cancel <-chan struct{}
+	// Scheduler 가 nil 이 아니면 그 평가가 교착 상태일 때 ErrDeadlock 을 반환한다.
+	// Cancel 을 평가 밖에서 닫을 수 있다면 교착 상태가 아니므로 nil 로 두어야 한다
+	Scheduler *Scheduler
+}
+
+// waiter 는 기다리고 있는 연산 하나. 다른 고루틴이 연산을 끝내주면 결과를 채우고 wake 를 닫는다
+type waiter struct {
+	wake   chan struct{}
+	cancel <-chan struct{}
+	sched  *Scheduler // 교착 상태를 확인하는 Scheduler. nil 이면 확인하지 않는다
+	done   bool
+
+	chosen int
+	value  Object
+	ok     bool
+	err    error
+
+	channels []*Channel // select 로 기다리는 채널들
+	task     *Task      // await 로 기다리는 Task
+}
+
+// pending 은 채널의 대기열에 들어간 연산
+type pending struct {
+	w     *waiter
+	index int    // select 의 case 인덱스
+	value Object // 보내는 값
+}
+
+// complete 는 w 의 결과를 채우고 대기열에서 빼낸 뒤 w 를 깨운다. mu 를 잠근 채로 호출해야 한다
+func (w *waiter) complete(index int, value Object, ok bool, err error) {
+	w.done = true
+	w.chosen, w.value, w.ok, w.err = index, value, ok, err
+
+	for _, c := range w.channels {
+		isW := func(p pending) bool { return p.w == w }
+		c.recvq = slices.DeleteFunc(c.recvq, isW)
+		c.sendq = slices.DeleteFunc(c.sendq, isW)
+	}
+	if w.task != nil {
+		w.task.waiters = slices.DeleteFunc(w.task.waiters, func(tw *waiter) bool { return tw == w })
+	}
+	if w.sched != nil {
+		delete(w.sched.blocked, w)
+	}
+	close(w.wake)
+}
+
+// park 는 다른 고루틴이 w 를 끝내주거나, wait.Cancel 이 닫히거나, 교착 상태가 될 때까지 기다린다.
+// mu 를 잠근 채로 호출해야 하며, 기다리는 동안에는 잠금을 풀었다가 다시 잠근 뒤 돌아온다.
+func park(w *waiter, wait Wait) {
+	w.cancel = wait.Cancel
+	if w.sched = wait.Scheduler; w.sched != nil {
+		w.sched.blocked[w] = struct{}{}
+		w.sched.checkDeadlock()
+	}
+	mu.Unlock()
+
+	select {
+	case <-w.wake:
+	case <-wait.Cancel:
+	}
+
+	mu.Lock()
+	// 취소되기 직전에 다른 고루틴이 연산을 끝냈다면 그 결과를 그대로 사용한다
+	if !w.done {
+		w.complete(-1, nil, false, ErrCanceled)
+	}
+}
+
+// checkDeadlock 은 실행 중인 고루틴이 모두 기다리고 있다면 기다리는 연산들을 ErrDeadlock 으로 깨운다.
+// 이미 취소되어 곧 깨어날 연산은 기다리는 것으로 보지 않는다.
+// await 는 기다리는 Task 가 에러로 끝나면 그 에러를 결과로 받을 수 있으므로 채널 연산이 있다면 그것만 깨운다.
+// mu 를 잠근 채로 호출해야 한다.
+func (sc *Scheduler) checkDeadlock() {
+	if len(sc.blocked) == 0 || len(sc.blocked) < sc.running {
+		return
+	}
+
+	var channels, tasks []*waiter
+	for w := range sc.blocked {
+		select {
+		case <-w.cancel:
+			return
+		default:
+		}
+		if w.task == nil {
+			channels = append(channels, w)
+		} else {
+			tasks = append(tasks, w)
+		}
+	}
+	waiters := channels
+	if len(waiters) == 0 {
+		waiters = tasks
+	}
+	for _, w := range waiters {
+		w.complete(-1, nil, false, ErrDeadlock)
+	}
+}
+
+// Task 는 spawn 으로 다른 고루틴에서 실행 중인 함수 호출을 표현하는 객체
+type Task struct {
+	done     chan struct{}
+	finished bool
+	result   Object
+	waiters  []*waiter
+	sched    *Scheduler
+	cancel   func()
+}
+
+// NewTask 는 새로운 고루틴에서 run 을 실행하는 Task 를 만든다. cancel 은 Cancel 이 호출되었을 때 run 을 멈추게 하는 함수이다.
+// sc 가 nil 이 아니면 Task 가 끝날 때까지 sc 의 실행 중인 고루틴으로 센다.
+// run 이 panic 하더라도 프로세스가 죽지 않도록 에러 객체를 결과로 남긴다.
+func NewTask(sc *Scheduler, run func() Object, cancel func()) *Task {
+	t := &Task{done: make(chan struct{}), sched: sc, cancel: cancel}
+
+	if sc != nil {
+		sc.Enter()
+	}
+
+	go func() {
+		var result Object
+		defer func() {
+			if r := recover(); r != nil {
+				result = &Error{Message: fmt.Sprintf("panic in spawned task: %v", r)}
+			}
+			t.finish(result)
+		}()
+		result = run()
+	}()
+
+	return t
+}
+
+func (t *Task) Type() ObjectType { return TASK_OBJ }
+func (t *Task) Inspect() string  { return "task" }
+
+// finish 는 Task 의 결과를 남기고 기다리던 연산들을 깨운다
+func (t *Task) finish(result Object) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	t.result = result
+	t.finished = true
+	for len(t.waiters) > 0 {
+		t.waiters[0].complete(0, result, true, nil)
+	}
+	close(t.done)
+
+	if t.sched != nil {
+		t.sched.running--
+		t.sched.checkDeadlock()
+	}
+}
+
+// Done 은 Task 의 실행이 끝나면 닫히는 채널을 반환한다.
+func (t *Task) Done() <-chan struct{} { return t.done }
+
+// Result 는 Task 의 실행 결과를 반환한다. Done 이 닫힌 이후에만 호출해야 한다.
+func (t *Task) Result() Object { return t.result }
+
+// Await 는 Task 가 끝날 때까지 기다려 그 결과를 반환한다.
+// 기다리는 도중 wait.Cancel 이 닫히면 ErrCanceled 를, 교착 상태가 되면 ErrDeadlock 을 반환한다.
+func (t *Task) Await(wait Wait) (Object, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if t.finished {
+		return t.result, nil
+	}
+	w := &waiter{wake: make(chan struct{}), task: t}
+	t.waiters = append(t.waiters, w)
+	park(w, wait)
+	return w.value, w.err
+}
+
+// Cancel 은 Task 를 만들 때 받은 cancel 을 호출하여 실행을 멈추도록 요청한다. 이미 끝난 Task 라면 아무 일도 하지 않는다.
+func (t *Task) Cancel() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+// Channel 은 고루틴 사이에서 객체를 주고받기 위한 채널.
+// Go 채널과 같은 의미를 갖지만 교착 상태를 찾을 수 있도록 하나의 잠금 아래에서 직접 구현한다.
+// 닫힌 채널에 값을 보내면 panic 대신 ErrChannelClosed 를 반환한다.
+type Channel struct {
+	capacity int
+	buf      []Object
+	closed   bool
+	recvq    []pending // 값을 받으려고 기다리는 연산들
+	sendq    []pending // 값을 보내려고 기다리는 연산들
+}
+
+func NewChannel(capacity int) *Channel {
+	return &Channel{capacity: capacity}
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (c *Channel) Inspect() string  { return fmt.Sprintf("channel(%d)", c.capacity) }
+
+// send 는 기다리지 않고 v 를 보내본다. 보낼 수 없다면 ready 로 false 를 반환한다
+func (c *Channel) send(v Object) (ready bool, err error) {
+	if c.closed {
+		return true, ErrChannelClosed
+	}
+	if len(c.recvq) > 0 {
+		p := c.recvq[0]
+		p.w.complete(p.index, v, true, nil)
+		return true, nil
+	}
+	if len(c.buf) < c.capacity {
+		c.buf = append(c.buf, v)
+		return true, nil
+	}
+	return false, nil
+}
+
+// recv 는 기다리지 않고 값을 받아본다. 받을 수 없다면 ready 로 false 를 반환한다
+func (c *Channel) recv() (v Object, ok, ready bool) {
+	if len(c.buf) > 0 {
+		v = c.buf[0]
+		c.buf[0] = nil
+		c.buf = c.buf[1:]
+		// 버퍼에 자리가 생겼으므로 기다리던 보내는 쪽의 값을 버퍼에 넣는다
+		if len(c.sendq) > 0 {
+			p := c.sendq[0]
+			c.buf = append(c.buf, p.value)
+			p.w.complete(p.index, nil, false, nil)
+		}
+		return v, true, true
+	}
+	if len(c.sendq) > 0 {
+		p := c.sendq[0]
+		p.w.complete(p.index, nil, false, nil)
+		return p.value, true, true
+	}
+	if c.closed {
+		return nil, false, true
+	}
+	return nil, false, false
+}
+
+// Send 는 값을 보낼 수 있을 때까지 기다렸다가 v 를 보낸다.
+// 채널이 닫혀 있다면 ErrChannelClosed 를, 기다리는 도중 취소되거나 교착 상태가 되면 ErrCanceled 나 ErrDeadlock 을 반환한다.
+func (c *Channel) Send(v Object, wait Wait) error {
+	_, _, _, err := Select([]SelectCase{{Chan: c, Send: true, Value: v}}, true, wait)
+	return err
+}
+
+// Recv 는 값을 받을 때까지 기다린다. 채널이 닫히고 남은 값이 없다면 ok 로 false 를 반환한다.
+// 기다리는 도중 취소되거나 교착 상태가 되면 ErrCanceled 나 ErrDeadlock 을 반환한다.
+func (c *Channel) Recv(wait Wait) (v Object, ok bool, err error) {
+	_, v, ok, err = Select([]SelectCase{{Chan: c}}, true, wait)
+	return v, ok, err
+}
+
+// Close 는 채널을 닫는다. 기다리던 받는 쪽은 값 없이, 보내는 쪽은 ErrChannelClosed 로 깨운다.
+// 이미 닫힌 채널이라면 ErrChannelClosed 를 반환한다.
+func (c *Channel) Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c.closed {
+		return ErrChannelClosed
+	}
+	c.closed = true
+	for len(c.recvq) > 0 {
+		p := c.recvq[0]
+		p.w.complete(p.index, nil, false, nil)
+	}
+	for len(c.sendq) > 0 {
+		p := c.sendq[0]
+		p.w.complete(p.index, nil, false, ErrChannelClosed)
+	}
+	return nil
+}
+
+// SelectCase 는 Select 로 기다릴 채널 연산 하나
+type SelectCase struct {
+	Chan  *Channel
+	Send  bool // true 이면 Value 를 보내고, false 이면 값을 받는다
+	Value Object
+}
+
+// Select 는 cases 중 준비된 연산 하나를 실행하고 그 인덱스를 반환한다. 여러 연산이 준비되어 있다면 무작위로 고른다.
+// 받는 연산이라면 받은 값을 반환하고, 채널이 닫혀서 받을 값이 없다면 ok 로 false 를 반환한다.
+// 닫힌 채널에 보내는 연산이 선택되면 ErrChannelClosed 를 반환한다.
+// block 이 false 이면 준비된 연산이 없을 때 기다리지 않고 -1 을 반환한다.
+func Select(cases []SelectCase, block bool, wait Wait) (chosen int, v Object, ok bool, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	start := 0
+	if len(cases) > 1 {
+		start = rand.IntN(len(cases))
+	}
+	for i := range cases {
+		index := (start + i) % len(cases)
+		c := cases[index]
+		if c.Send {
+			if ready, err := c.Chan.send(c.Value); ready {
+				return index, nil, false, err
+			}
+		} else if v, ok, ready := c.Chan.recv(); ready {
+			return index, v, ok, nil
+		}
+	}
+	if !block {
+		return -1, nil, false, nil
+	}
+
+	w := &waiter{wake: make(chan struct{})}
+	for i, c := range cases {
+		p := pending{w: w, index: i, value: c.Value}
+		if c.Send {
+			c.Chan.sendq = append(c.Chan.sendq, p)
+		} else {
+			c.Chan.recvq = append(c.Chan.recvq, p)
+		}
+		w.channels = append(w.channels, c.Chan)
+	}
+	park(w, wait)
+	return w.chosen, w.value, w.ok, w.err
+}
@@ -0,0 +1,541 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var (
+	objectType         = reflect.TypeOf((*Object)(nil)).Elem()
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// FromGo 는 Go 값을 Monkey 객체로 변환한다.
+//
+//   - nil 과 nil 포인터는 NULL, bool 은 TRUE/FALSE, 정수 타입은 Integer, string 은 String 이 된다.
+//   - 슬라이스와 배열은 Array, 맵과 구조체는 Hash 가 된다.
+//     구조체의 필드 이름은 `monkey:"name"` 태그로 바꿀 수 있고, "-" 태그가 붙은 필드와 비공개 필드는 제외된다.
+//   - 함수는 인자를 ToGo 로, 반환값을 FromGo 로 변환하는 Builtin 이 된다.
+//     마지막 반환값이 error 이고 nil 이 아니라면 Builtin 은 그 에러를 Cause 로 갖는 Error 를 반환한다.
+//   - 이미 Object 인 값은 그대로 반환한다.
+func FromGo(v interface{}) (Object, error) {
+	if v == nil {
+		return NULL, nil
+	}
+	if obj, ok := v.(Object); ok {
+		return obj, nil
+	}
+	return fromValue(reflect.ValueOf(v))
+}
+
+func fromValue(v reflect.Value) (Object, error) {
+	return convertValue(v, map[visit]bool{})
+}
+
+// visit 은 변환 중인 포인터, 맵, 슬라이스를 구분한다. 같은 주소라도 타입이나 길이가 다르면 다른 값이다
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// visitOf 는 v 가 다른 값을 가리키는 값이라면 v 를 구분하는 visit 을 반환한다
+func visitOf(v reflect.Value) (visit, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		// 크기가 0 인 값들은 주소를 공유할 수 있지만 다른 값을 담을 수 없으므로 순환할 수 없다
+		if v.Type().Elem().Size() == 0 {
+			return visit{}, false
+		}
+		return visit{v.Pointer(), v.Type(), 0}, true
+	case reflect.Map:
+		if v.IsNil() {
+			return visit{}, false
+		}
+		return visit{v.Pointer(), v.Type(), 0}, true
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return visit{}, false
+		}
+		return visit{v.Pointer(), v.Type(), v.Len()}, true
+	}
+	return visit{}, false
+}
+
+// convertValue 는 v 를 변환한다. visiting 은 v 를 담고 있어 변환 중인 값들이다.
+// 자기 자신을 가리키는 값은 끝없이 재귀하다 복구할 수 없는 스택 오버플로로 프로세스를 죽이므로,
+// 변환 중인 값을 다시 만나면 에러를 반환한다. 순환하지 않고 여러 번 나오는 값은 그대로 여러 번 변환한다.
+func convertValue(v reflect.Value, visiting map[visit]bool) (Object, error) {
+	if !v.IsValid() {
+		return NULL, nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return NULL, nil
+	}
+	if v.Type().Implements(objectType) {
+		return v.Interface().(Object), nil
+	}
+
+	if key, ok := visitOf(v); ok {
+		if visiting[key] {
+			return nil, fmt.Errorf("cyclic Go value of type %s", v.Type())
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return NativeBoolToBoolean(v.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > 1<<63-1 {
+			return nil, fmt.Errorf("Go value %d overflows INTEGER", u)
+		}
+		return &Integer{Value: int64(u)}, nil
+
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+
+	case reflect.Ptr, reflect.Interface:
+		return convertValue(v.Elem(), visiting)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return &Array{Elements: []Object{}}, nil
+		}
+		elements := make([]Object, v.Len())
+		for i := range elements {
+			el, err := convertValue(v.Index(i), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = el
+		}
+		return &Array{Elements: elements}, nil
+
+	case reflect.Map:
+		pairs := make([]HashPair, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := convertValue(iter.Key(), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("map key: %w", err)
+			}
+			if _, ok := AsHashable(key); !ok {
+				return nil, fmt.Errorf("unusable as hash key: %s", key.Type())
+			}
+			value, err := convertValue(iter.Value(), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("map value %s: %w", key.Inspect(), err)
+			}
+			pairs = append(pairs, HashPair{Key: key, Value: value})
+		}
+
+		// Go 맵의 순회 순서는 매번 다르므로 키의 순서대로 정렬하여 항상 같은 해시를 만든다
+		sortPairs(pairs)
+		hash := NewHash()
+		for _, pair := range pairs {
+			hash.Set(pair.Key.(Hashable), pair.Value)
+		}
+		return hash, nil
+
+	case reflect.Struct:
+		hash := NewHash()
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := fieldName(t.Field(i))
+			if !ok {
+				continue
+			}
+			value, err := convertValue(v.Field(i), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+			hash.Set(&String{Value: name}, value)
+		}
+		return hash, nil
+
+	case reflect.Func:
+		if v.IsNil() {
+			return NULL, nil
+		}
+		return fromFunc(v), nil
+	}
+
+	return nil, fmt.Errorf("unsupported Go type %s", v.Type())
+}
+
+// fromFunc 는 Go 함수를 감싸는 Builtin 을 만든다.
+func fromFunc(fn reflect.Value) *Builtin {
+	t := fn.Type()
+
+	return &Builtin{Fn: func(args ...Object) Object {
+		numIn := t.NumIn()
+		if t.IsVariadic() {
+			if len(args) < numIn-1 {
+				return &Error{Message: fmt.Sprintf(
+					"wrong number of arguments. got=%d, want at least %d",
+					len(args), numIn-1)}
+			}
+		} else if len(args) != numIn {
+			return &Error{Message: fmt.Sprintf(
+				"wrong number of arguments. got=%d, want=%d", len(args), numIn)}
+		}
+
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			var argType reflect.Type
+			if t.IsVariadic() && i >= numIn-1 {
+				argType = t.In(numIn - 1).Elem()
+			} else {
+				argType = t.In(i)
+			}
+
+			argValue := reflect.New(argType)
+			if err := toValue(arg, argValue.Elem()); err != nil {
+				return &Error{Message: fmt.Sprintf("argument %d: %s", i+1, err)}
+			}
+			in[i] = argValue.Elem()
+		}
+
+		out := fn.Call(in)
+
+		// 마지막 반환값이 error 라면 Monkey 의 에러 객체로 바꾼다
+		if len(out) > 0 && t.Out(len(out)-1) == errorType {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return &Error{Message: err.Error(), Cause: err}
+			}
+			out = out[:len(out)-1]
+		}
+
+		if len(out) == 0 {
+			return NULL
+		}
+
+		result, err := fromValue(out[0])
+		if err != nil {
+			return &Error{Message: fmt.Sprintf("return value: %s", err)}
+		}
+		return result
+	}}
+}
+
+// ToGo 는 Monkey 객체를 target 이 가리키는 Go 값으로 변환한다. target 은 nil 이 아닌 포인터여야 한다.
+//
+// Integer 는 정수 타입, String 은 string, Boolean 은 bool, Array 는 슬라이스나 배열,
+// Hash 는 맵이나 구조체로 변환된다. target 이 interface{} 라면 Integer 는 int64, Array 는 []interface{},
+// Hash 는 map[interface{}]interface{} 가 된다. Builtin 은 같은 시그니처의 Go 함수로 변환할 수 있다.
+// NULL 은 대상 타입의 0 값이 된다.
+func ToGo(obj Object, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	return toValue(obj, v.Elem())
+}
+
+func toValue(obj Object, v reflect.Value) error {
+	if obj == nil {
+		obj = NULL
+	}
+	t := v.Type()
+
+	// Object 를 그대로 받을 수 있는 대상이라면 변환하지 않는다
+	if t.Kind() == reflect.Interface && t != emptyInterfaceType && reflect.TypeOf(obj).Implements(t) {
+		v.Set(reflect.ValueOf(obj))
+		return nil
+	}
+	if reflect.TypeOf(obj) == t {
+		v.Set(reflect.ValueOf(obj))
+		return nil
+	}
+
+	if _, ok := obj.(*Null); ok {
+		v.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t != emptyInterfaceType {
+			break
+		}
+		native, err := toNative(obj)
+		if err != nil {
+			return err
+		}
+		if native != nil {
+			v.Set(reflect.ValueOf(native))
+		}
+		return nil
+
+	case reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		if err := toValue(obj, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+
+	case reflect.Bool:
+		if b, ok := obj.(*Boolean); ok {
+			v.SetBool(b.Value)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := obj.(*Integer); ok {
+			if v.OverflowInt(i.Value) {
+				return fmt.Errorf("%d overflows Go %s", i.Value, t)
+			}
+			v.SetInt(i.Value)
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := obj.(*Integer); ok {
+			if i.Value < 0 || v.OverflowUint(uint64(i.Value)) {
+				return fmt.Errorf("%d overflows Go %s", i.Value, t)
+			}
+			v.SetUint(uint64(i.Value))
+			return nil
+		}
+
+	case reflect.String:
+		if s, ok := obj.(*String); ok {
+			v.SetString(s.Value)
+			return nil
+		}
+
+	case reflect.Slice:
+		if arr, ok := obj.(*Array); ok {
+			slice := reflect.MakeSlice(t, len(arr.Elements), len(arr.Elements))
+			for i, el := range arr.Elements {
+				if err := toValue(el, slice.Index(i)); err != nil {
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			v.Set(slice)
+			return nil
+		}
+
+	case reflect.Array:
+		if arr, ok := obj.(*Array); ok {
+			if len(arr.Elements) != t.Len() {
+				return fmt.Errorf("cannot convert ARRAY of length %d to Go %s",
+					len(arr.Elements), t)
+			}
+			for i, el := range arr.Elements {
+				if err := toValue(el, v.Index(i)); err != nil {
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			return nil
+		}
+
+	case reflect.Map:
+		if hash, ok := obj.(*Hash); ok {
+			m := reflect.MakeMapWithSize(t, hash.Len())
+			for _, pair := range hash.Pairs() {
+				key := reflect.New(t.Key()).Elem()
+				if err := toValue(pair.Key, key); err != nil {
+					return fmt.Errorf("hash key %s: %w", pair.Key.Inspect(), err)
+				}
+				// interface{} 키에 배열을 넣으면 비교할 수 없는 슬라이스가 되어 SetMapIndex 가 panic 한다
+				if !key.Comparable() {
+					return fmt.Errorf("hash key %s: cannot use Go %s as map key", pair.Key.Inspect(), key.Elem().Type())
+				}
+				value := reflect.New(t.Elem()).Elem()
+				if err := toValue(pair.Value, value); err != nil {
+					return fmt.Errorf("hash value %s: %w", pair.Key.Inspect(), err)
+				}
+				m.SetMapIndex(key, value)
+			}
+			v.Set(m)
+			return nil
+		}
+
+	case reflect.Struct:
+		if hash, ok := obj.(*Hash); ok {
+			for i := 0; i < t.NumField(); i++ {
+				name, ok := fieldName(t.Field(i))
+				if !ok {
+					continue
+				}
+				// 해시에 없는 필드는 0 값으로 남겨둔다
+				value, ok := hash.Get(&String{Value: name})
+				if !ok {
+					continue
+				}
+				if err := toValue(value, v.Field(i)); err != nil {
+					return fmt.Errorf("field %s: %w", name, err)
+				}
+			}
+			return nil
+		}
+
+	case reflect.Func:
+		if b, ok := obj.(*Builtin); ok {
+			v.Set(toFunc(b, t))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot convert %s to Go %s", obj.Type(), t)
+}
+
+// toNative 는 대상 타입이 정해지지 않은 경우(interface{}) 객체에 대응하는 기본 Go 값을 만든다.
+func toNative(obj Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case *Null:
+		return nil, nil
+	case *Integer:
+		return obj.Value, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Array:
+		elements := make([]interface{}, len(obj.Elements))
+		for i, el := range obj.Elements {
+			native, err := toNative(el)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = native
+		}
+		return elements, nil
+	case *Hash:
+		m := make(map[interface{}]interface{}, obj.Len())
+		for _, pair := range obj.Pairs() {
+			key, err := toNative(pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			// 배열 키는 []interface{} 가 되므로 Go 맵의 키로 사용할 수 없다
+			if key != nil && !reflect.ValueOf(key).Comparable() {
+				return nil, fmt.Errorf("hash key %s: cannot use Go %T as map key", pair.Key.Inspect(), key)
+			}
+			value, err := toNative(pair.Value)
+			if err != nil {
+				return nil, fmt.Errorf("hash value %s: %w", pair.Key.Inspect(), err)
+			}
+			m[key] = value
+		}
+		return m, nil
+	default:
+		// 함수처럼 대응하는 Go 값이 없는 객체는 그대로 넘겨준다
+		return obj, nil
+	}
+}
+
+// toFunc 는 Builtin 을 호출하는 t 타입의 Go 함수를 만든다.
+// 반환 타입의 마지막이 error 라면 Builtin 이 반환한 에러 객체와 변환 에러가 그 자리로 전달되고,
+// 그렇지 않다면 에러가 발생했을 때 panic 한다.
+func toFunc(b *Builtin, t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.New(t.Out(i)).Elem()
+		}
+
+		fail := func(err error) []reflect.Value {
+			if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+				panic(err)
+			}
+			out[t.NumOut()-1].Set(reflect.ValueOf(&err).Elem())
+			return out
+		}
+
+		args := make([]Object, 0, len(in))
+		for i, arg := range in {
+			if t.IsVariadic() && i == len(in)-1 {
+				for j := 0; j < arg.Len(); j++ {
+					obj, err := fromValue(arg.Index(j))
+					if err != nil {
+						return fail(err)
+					}
+					args = append(args, obj)
+				}
+				continue
+			}
+			obj, err := fromValue(arg)
+			if err != nil {
+				return fail(err)
+			}
+			args = append(args, obj)
+		}
+
+		// Callback 만 가진 내장 함수는 평가기 없이 호출할 수 없다
+		if b.Fn == nil {
+			return fail(fmt.Errorf("builtin function must be called by the evaluator"))
+		}
+
+		result := b.Fn(args...)
+		if errObj, ok := result.(*Error); ok {
+			return fail(errObj)
+		}
+
+		if t.NumOut() > 0 && t.Out(0) != errorType {
+			if err := toValue(result, out[0]); err != nil {
+				return fail(err)
+			}
+		}
+		return out
+	})
+}
+
+// fieldName 은 구조체 필드가 해시의 키로 사용할 이름과, 변환 대상인지 여부를 반환한다.
+func fieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	tag := field.Tag.Get("monkey")
+	if tag == "-" {
+		return "", false
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return field.Name, true
+}
+
+// sortPairs 는 키의 순서대로 쌍들을 정렬한다. 정수, 불리언, 문자열 순이며 같은 타입끼리는 값의 순서를 따른다.
+func sortPairs(pairs []HashPair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i].Key, pairs[j].Key
+		if a.Type() != b.Type() {
+			return keyTypeOrder(a) < keyTypeOrder(b)
+		}
+		switch a := a.(type) {
+		case *Integer:
+			return a.Value < b.(*Integer).Value
+		case *Boolean:
+			return !a.Value && b.(*Boolean).Value
+		case *String:
+			return a.Value < b.(*String).Value
+		}
+		return a.Inspect() < b.Inspect()
+	})
+}
+
+func keyTypeOrder(key Object) int {
+	switch key.Type() {
+	case INTEGER_OBJ:
+		return 0
+	case BOOLEAN_OBJ:
+		return 1
+	case STRING_OBJ:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,335 @@
+package object
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	Name    string
+	Age     int  `monkey:"age"`
+	Admin   bool `monkey:"-"`
+	Tags    []string
+	private int
+}
+
+func TestFromGo(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{true, "true"},
+		{false, "false"},
+		{42, "42"},
+		{int8(-3), "-3"},
+		{uint16(7), "7"},
+		{"hello", "hello"},
+		{[]int{1, 2, 3}, "[1, 2, 3]"},
+		{[2]string{"a", "b"}, "[a, b]"},
+		{[]interface{}{1, "two", nil}, "[1, two, null]"},
+		{map[string]int{"one": 1}, "{one: 1}"},
+		{(*int)(nil), "null"},
+		{&Integer{Value: 5}, "5"},
+		{
+			testUser{Name: "kim", Age: 30, Admin: true, Tags: []string{"x"}},
+			"Name: kim|Tags: [x]|age: 30",
+		},
+	}
+
+	for _, tt := range tests {
+		obj, err := FromGo(tt.input)
+		if err != nil {
+			t.Errorf("FromGo(%#v) returned error: %s", tt.input, err)
+			continue
+		}
+
+		got := obj.Inspect()
+		if hash, ok := obj.(*Hash); ok && hash.Len() > 1 {
+			got = inspectSortedPairs(hash)
+		}
+		if got != tt.expected {
+			t.Errorf("FromGo(%#v) wrong. expected=%q, got=%q",
+				tt.input, tt.expected, got)
+		}
+	}
+
+	if obj, _ := FromGo(true); obj != TRUE {
+		t.Errorf("FromGo(true) is not TRUE singleton")
+	}
+	if obj, _ := FromGo(nil); obj != NULL {
+		t.Errorf("FromGo(nil) is not NULL singleton")
+	}
+}
+
+func TestFromGoErrors(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+	}{
+		{3.14, "unsupported Go type float64"},
+		{uint64(1 << 63), "Go value 9223372036854775808 overflows INTEGER"},
+		{[]interface{}{1, 2.5}, "index 1: unsupported Go type float64"},
+		{map[float64]int{1.5: 1}, "map key: unsupported Go type float64"},
+	}
+
+	for _, tt := range tests {
+		_, err := FromGo(tt.input)
+		if err == nil {
+			t.Errorf("FromGo(%#v) did not return error", tt.input)
+			continue
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, err.Error())
+		}
+	}
+}
+
+type cyclicNode struct {
+	Value int
+	Next  *cyclicNode
+}
+
+func TestFromGoCycles(t *testing.T) {
+	// 자기 자신을 가리키는 값은 스택이 넘치기 전에 에러를 반환한다
+	node := &cyclicNode{Value: 1}
+	node.Next = &cyclicNode{Value: 2, Next: node}
+	m := map[string]interface{}{}
+	m["self"] = m
+	s := []interface{}{nil}
+	s[0] = s
+
+	tests := []struct {
+		input    interface{}
+		expected string
+	}{
+		{node, "field Next: field Next: cyclic Go value of type *object.cyclicNode"},
+		{m, "map value self: cyclic Go value of type map[string]interface {}"},
+		{s, "index 0: cyclic Go value of type []interface {}"},
+	}
+	for _, tt := range tests {
+		_, err := FromGo(tt.input)
+		if err == nil {
+			t.Errorf("FromGo(%T) did not return error", tt.input)
+			continue
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, err.Error())
+		}
+	}
+
+	// 순환하지 않고 여러 번 나오는 값은 에러가 아니다
+	shared := &cyclicNode{Value: 3}
+	obj, err := FromGo([]*cyclicNode{shared, shared})
+	if err != nil {
+		t.Fatalf("FromGo of shared value returned error: %s", err)
+	}
+	if obj.Inspect() != `[{Value: 3, Next: null}, {Value: 3, Next: null}]` {
+		t.Errorf("wrong object. got=%s", obj.Inspect())
+	}
+}
+
+func TestFromGoFunc(t *testing.T) {
+	add, err := FromGo(func(a, b int) int { return a + b })
+	if err != nil {
+		t.Fatalf("FromGo returned error: %s", err)
+	}
+	builtin, ok := add.(*Builtin)
+	if !ok {
+		t.Fatalf("object is not Builtin. got=%T", add)
+	}
+
+	result := builtin.Fn(&Integer{Value: 1}, &Integer{Value: 2})
+	if result.Inspect() != "3" {
+		t.Errorf("wrong result. got=%s", result.Inspect())
+	}
+
+	result = builtin.Fn(&Integer{Value: 1})
+	testErrorMessage(t, result, "wrong number of arguments. got=1, want=2")
+
+	result = builtin.Fn(&Integer{Value: 1}, &String{Value: "x"})
+	testErrorMessage(t, result, "argument 2: cannot convert STRING to Go int")
+
+	joinErr := errors.New("empty input")
+	join, _ := FromGo(func(sep string, parts ...string) (string, error) {
+		if len(parts) == 0 {
+			return "", joinErr
+		}
+		return strings.Join(parts, sep), nil
+	})
+
+	result = join.(*Builtin).Fn(&String{Value: "-"}, &String{Value: "a"}, &String{Value: "b"})
+	if result.Inspect() != "a-b" {
+		t.Errorf("wrong result. got=%s", result.Inspect())
+	}
+
+	result = join.(*Builtin).Fn(&String{Value: "-"})
+	testErrorMessage(t, result, "empty input")
+	if errObj, ok := result.(*Error); !ok || !errors.Is(errObj, joinErr) {
+		t.Errorf("error does not wrap Go error. got=%+v", result)
+	}
+
+	noop, _ := FromGo(func() {})
+	if noop.(*Builtin).Fn() != NULL {
+		t.Errorf("function without results does not return NULL")
+	}
+}
+
+func TestToGo(t *testing.T) {
+	var i int
+	if err := ToGo(&Integer{Value: 7}, &i); err != nil || i != 7 {
+		t.Errorf("ToGo int wrong. got=%d, err=%v", i, err)
+	}
+
+	var s string
+	if err := ToGo(&String{Value: "hi"}, &s); err != nil || s != "hi" {
+		t.Errorf("ToGo string wrong. got=%q, err=%v", s, err)
+	}
+
+	var b bool
+	if err := ToGo(TRUE, &b); err != nil || !b {
+		t.Errorf("ToGo bool wrong. got=%t, err=%v", b, err)
+	}
+
+	var ints []int64
+	arr := &Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}
+	if err := ToGo(arr, &ints); err != nil || !reflect.DeepEqual(ints, []int64{1, 2}) {
+		t.Errorf("ToGo slice wrong. got=%v, err=%v", ints, err)
+	}
+
+	hash, _ := FromGo(map[string]interface{}{
+		"Name": "lee",
+		"age":  41,
+		"Tags": []string{"a", "b"},
+	})
+
+	var user testUser
+	if err := ToGo(hash, &user); err != nil {
+		t.Fatalf("ToGo struct returned error: %s", err)
+	}
+	expectedUser := testUser{Name: "lee", Age: 41, Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(user, expectedUser) {
+		t.Errorf("ToGo struct wrong. expected=%+v, got=%+v", expectedUser, user)
+	}
+
+	var m map[string]int
+	pairs := NewHash()
+	pairs.Set(&String{Value: "x"}, &Integer{Value: 1})
+	if err := ToGo(pairs, &m); err != nil || m["x"] != 1 {
+		t.Errorf("ToGo map wrong. got=%v, err=%v", m, err)
+	}
+
+	var native interface{}
+	if err := ToGo(arr, &native); err != nil ||
+		!reflect.DeepEqual(native, []interface{}{int64(1), int64(2)}) {
+		t.Errorf("ToGo interface wrong. got=%#v, err=%v", native, err)
+	}
+
+	var ptr *int
+	if err := ToGo(NULL, &ptr); err != nil || ptr != nil {
+		t.Errorf("ToGo NULL wrong. got=%v, err=%v", ptr, err)
+	}
+
+	var obj Object
+	if err := ToGo(arr, &obj); err != nil || obj != arr {
+		t.Errorf("ToGo Object wrong. got=%v, err=%v", obj, err)
+	}
+}
+
+func TestToGoErrors(t *testing.T) {
+	var i int8
+	var s string
+	var fixed [3]int
+	var u uint
+	var native interface{}
+
+	// 배열을 키로 하는 해시는 interface{} 키의 Go 맵으로 변환할 수 없다
+	arrayKey := NewHash()
+	arrayKey.Set(&Array{Elements: []Object{&Integer{Value: 1}}}, &Integer{Value: 2})
+
+	tests := []struct {
+		obj      Object
+		target   interface{}
+		expected string
+	}{
+		{&Integer{Value: 1}, i, "target must be a non-nil pointer, got int8"},
+		{&Integer{Value: 300}, &i, "300 overflows Go int8"},
+		{&Integer{Value: -1}, &u, "-1 overflows Go uint"},
+		{&Integer{Value: 1}, &s, "cannot convert INTEGER to Go string"},
+		{&Array{Elements: []Object{}}, &fixed, "cannot convert ARRAY of length 0 to Go [3]int"},
+		{
+			&Array{Elements: []Object{&String{Value: "x"}}},
+			&[]int{},
+			"index 0: cannot convert STRING to Go int",
+		},
+		{arrayKey, &native, "hash key [1]: cannot use Go []interface {} as map key"},
+		{arrayKey, &map[interface{}]int64{}, "hash key [1]: cannot use Go []interface {} as map key"},
+	}
+
+	for _, tt := range tests {
+		err := ToGo(tt.obj, tt.target)
+		if err == nil {
+			t.Errorf("ToGo(%s) did not return error", tt.obj.Inspect())
+			continue
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, err.Error())
+		}
+	}
+}
+
+func TestToGoFunc(t *testing.T) {
+	builtin := &Builtin{Fn: func(args ...Object) Object {
+		if len(args) != 1 {
+			return &Error{Message: "want one argument"}
+		}
+		return &Integer{Value: args[0].(*Integer).Value * 2}
+	}}
+
+	var double func(int) (int, error)
+	if err := ToGo(builtin, &double); err != nil {
+		t.Fatalf("ToGo func returned error: %s", err)
+	}
+
+	result, err := double(21)
+	if err != nil || result != 42 {
+		t.Errorf("double(21) wrong. got=%d, err=%v", result, err)
+	}
+
+	var variadic func(...int) (int, error)
+	if err := ToGo(builtin, &variadic); err != nil {
+		t.Fatalf("ToGo func returned error: %s", err)
+	}
+
+	_, err = variadic(1, 2)
+	if err == nil || err.Error() != "want one argument" {
+		t.Errorf("variadic(1, 2) wrong error. got=%v", err)
+	}
+}
+
+func testErrorMessage(t *testing.T, obj Object, expected string) bool {
+	errObj, ok := obj.(*Error)
+	if !ok {
+		t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q",
+			expected, errObj.Message)
+		return false
+	}
+	return true
+}
+
+// inspectSortedPairs 는 순서가 정해지지 않은 해시의 쌍을 키 순으로 정렬하여 비교할 수 있게 만든다.
+func inspectSortedPairs(hash *Hash) string {
+	pairs := []string{}
+	for _, pair := range hash.Pairs() {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "|")
+}
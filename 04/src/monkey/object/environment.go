@@ -1,5 +1,26 @@
 package object
 
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Environment 는 이름과 객체의 바인딩을 저장하며 여러 고루틴에서 동시에 사용할 수 있다.
+//
+// 동시성 모델:
+//   - 얼리지 않은(Freeze 하지 않은) 환경은 내부의 RWMutex 로 Get 과 Set 을 보호한다.
+//   - Freeze 한 환경은 더 이상 바뀌지 않으므로 Get 이 잠금 없이 동작한다. Freeze 이후의 Set 은 panic 한다.
+//   - 여러 스크립트를 병렬로 평가하려면 전역 바인딩을 담은 환경을 Freeze 한 뒤,
+//     실행마다 NewEnclosedEnvironment(globals) 로 만든 자식 환경에서 평가한다.
+//     let 으로 만든 바인딩은 자식 환경에만 저장되므로 실행끼리 서로 영향을 주지 않는다.
+type Environment struct {
+	mu     sync.RWMutex
+	store  map[string]Object
+	outer  *Environment
+	frozen atomic.Bool
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
@@ -11,13 +32,18 @@ func NewEnvironment() *Environment {
 	return &Environment{store: s, outer: nil}
 }
 
-type Environment struct {
-	store map[string]Object
-	outer *Environment
-}
-
 func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]
+	var obj Object
+	var ok bool
+
+	if e.frozen.Load() {
+		obj, ok = e.store[name]
+	} else {
+		e.mu.RLock()
+		obj, ok = e.store[name]
+		e.mu.RUnlock()
+	}
+
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
@@ -25,6 +51,45 @@ func (e *Environment) Get(name string) (Object, bool) {
 }
 
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.frozen.Load() {
+		panic("object: Set called on frozen environment")
+	}
+
 	e.store[name] = val
 	return val
 }
+
+// Freeze 는 환경을 읽기 전용으로 만든다. 바깥 환경은 얼리지 않는다.
+func (e *Environment) Freeze() {
+	e.mu.Lock()
+	e.frozen.Store(true)
+	e.mu.Unlock()
+}
+
+// Frozen 은 환경이 Freeze 되었는지 여부를 반환한다.
+func (e *Environment) Frozen() bool {
+	return e.frozen.Load()
+}
+
+// Names 는 이 환경에 직접 바인딩된 이름들을 정렬해서 반환한다. 바깥 환경의 이름은 포함하지 않는다.
+func (e *Environment) Names() []string {
+	if !e.frozen.Load() {
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+	}
+
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Outer 는 바깥 환경을 반환한다. 가장 바깥의 환경이라면 nil 을 반환한다.
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
@@ -0,0 +1,80 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestEnvironmentConcurrentAccess(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("shared", &Integer{Value: 1})
+	env := NewEnclosedEnvironment(outer)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				name := fmt.Sprintf("v%d", i)
+				env.Set(name, &Integer{Value: int64(j)})
+				if _, ok := env.Get(name); !ok {
+					t.Errorf("%s is not bound", name)
+				}
+				if _, ok := env.Get("shared"); !ok {
+					t.Errorf("shared is not bound")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestEnvironmentFreeze(t *testing.T) {
+	globals := NewEnvironment()
+	globals.Set("x", &Integer{Value: 1})
+	globals.Freeze()
+
+	if !globals.Frozen() {
+		t.Fatalf("environment is not frozen")
+	}
+
+	child := NewEnclosedEnvironment(globals)
+	child.Set("y", &Integer{Value: 2})
+	if child.Frozen() {
+		t.Errorf("child of frozen environment is frozen")
+	}
+	if _, ok := child.Get("x"); !ok {
+		t.Errorf("x is not visible from child")
+	}
+	if _, ok := globals.Get("y"); ok {
+		t.Errorf("y leaked into frozen environment")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Set on frozen environment did not panic")
+		}
+	}()
+	globals.Set("x", &Integer{Value: 3})
+}
+
+func TestEnvironmentNames(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("z", &Integer{Value: 1})
+	env := NewEnclosedEnvironment(outer)
+	env.Set("b", &Integer{Value: 2})
+	env.Set("a", &Integer{Value: 3})
+
+	names := env.Names()
+	if fmt.Sprint(names) != "[a b]" {
+		t.Errorf("env.Names() wrong. got=%v", names)
+	}
+	if env.Outer() != outer {
+		t.Errorf("env.Outer() is not outer")
+	}
+	if outer.Outer() != nil {
+		t.Errorf("outer.Outer() is not nil")
+	}
+}
@@ -0,0 +1,172 @@
+package object
+
+import "hash/fnv"
+
+// StringHash 는 문자열 키의 HashKey 값을 계산하는 함수이다.
+// 서로 다른 문자열의 해시 값이 같더라도 Hash 는 실제 키를 비교하므로 값을 잃지 않는다.
+// 테스트에서 해시 충돌을 만들기 위해 바꿀 수 있으며, 평가 중에는 바꾸어서는 안 된다.
+var StringHash = func(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// HashPair 맵 자료형의 값을 표현하는 객체
+// monkey 언에서 map 은 HashKey 로 HashPair 를 찾는 구조로 표현되는데,
+// key: value 가 아닌 hashkey: hashpair 로 표현하는 이유는
+// key 가 겉보기에는 같더라도 실제로는 메모리상 다른 주소를 가진 값일 수 있기 때문이다. 이 경우 제대로 value 를 찾아낼 수 없다.
+// 그렇기에 hashkey 를 통해 hashpair 를 찾고 겉보기의 key 값을 hashpair 에 저장한다.
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash 해시 리터럴을 평가하기 위한 객체
+// 키-값 쌍을 추가한 순서대로 저장하므로 Inspect 와 Pairs 로 순회한 결과는 항상 같은 순서이다.
+// 키로 값을 찾을 때에는 HashKey 로 후보를 찾은 뒤 실제 키를 비교하므로,
+// 서로 다른 키의 HashKey 가 충돌하더라도 값을 덮어쓰지 않는다. 0 값은 빈 해시로 사용할 수 있다.
+type Hash struct {
+	pairs []HashPair        // 추가한 순서대로 저장한 키-값 쌍
+	index map[HashKey][]int // HashKey 가 같은 쌍들의 pairs 에서의 위치
+}
+
+// NewHash 는 빈 해시를 만든다.
+func NewHash() *Hash {
+	return &Hash{index: make(map[HashKey][]int)}
+}
+
+// Set 은 key 에 value 를 저장한다. 이미 있는 키라면 순서는 그대로 두고 값만 바꾼다.
+func (h *Hash) Set(key Hashable, value Object) {
+	if h.index == nil {
+		h.index = make(map[HashKey][]int)
+	}
+
+	hashKey := key.HashKey()
+	if i := h.find(hashKey, key); i >= 0 {
+		h.pairs[i].Value = value
+		return
+	}
+
+	h.index[hashKey] = append(h.index[hashKey], len(h.pairs))
+	h.pairs = append(h.pairs, HashPair{Key: key, Value: value})
+}
+
+// Get 은 key 에 저장된 값을 찾는다.
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	i := h.find(key.HashKey(), key)
+	if i < 0 {
+		return nil, false
+	}
+	return h.pairs[i].Value, true
+}
+
+// Delete 는 key 와 그 값을 지우고, 키가 있었는지 여부를 반환한다.
+// 뒤에 있는 쌍들의 위치를 다시 계산하므로 O(n) 이다.
+func (h *Hash) Delete(key Hashable) bool {
+	i := h.find(key.HashKey(), key)
+	if i < 0 {
+		return false
+	}
+
+	// 이전에 Pairs 로 반환한 슬라이스가 바뀌지 않도록 새로운 배열에 복사한다
+	h.pairs = append(h.pairs[:i:i], h.pairs[i+1:]...)
+
+	h.index = make(map[HashKey][]int, len(h.pairs))
+	for j, pair := range h.pairs {
+		hashKey := pair.Key.(Hashable).HashKey()
+		h.index[hashKey] = append(h.index[hashKey], j)
+	}
+	return true
+}
+
+// Len 은 키-값 쌍의 개수를 반환한다.
+func (h *Hash) Len() int { return len(h.pairs) }
+
+// Pairs 는 키-값 쌍들을 추가한 순서대로 반환한다. 반환된 슬라이스를 바꾸어서는 안 된다.
+func (h *Hash) Pairs() []HashPair { return h.pairs }
+
+// find 는 HashKey 가 hashKey 인 쌍들 중에서 key 와 같은 키를 가진 쌍의 위치를 찾는다. 없다면 -1 을 반환한다.
+func (h *Hash) find(hashKey HashKey, key Object) int {
+	for _, i := range h.index[hashKey] {
+		if KeysEqual(h.pairs[i].Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// AsHashable 은 obj 를 해시 키로 사용할 수 있다면 Hashable 로 반환한다.
+// 배열은 모든 원소를 해시 키로 사용할 수 있을 때에만 해시 키가 될 수 있다.
+func AsHashable(obj Object) (Hashable, bool) {
+	key, ok := obj.(Hashable)
+	if !ok {
+		return nil, false
+	}
+
+	if arr, ok := obj.(*Array); ok {
+		for _, e := range arr.Elements {
+			if _, ok := AsHashable(e); !ok {
+				return nil, false
+			}
+		}
+	}
+	return key, true
+}
+
+// KeysEqual 은 두 해시 키가 같은 키인지 비교한다.
+// 내장 타입은 Equal 로 값을 비교하고, 호스트가 정의한 Hashable 객체는 HashKey 가 같으면 같은 키로 본다.
+func KeysEqual(a, b Object) bool {
+	switch a.(type) {
+	case *String, *Integer, *Boolean, *Array:
+		return Equal(a, b)
+	}
+
+	ak, aok := a.(Hashable)
+	bk, bok := b.(Hashable)
+	return aok && bok && ak.HashKey() == bk.HashKey()
+}
+
+// Equal 은 두 객체가 같은 값인지 비교한다.
+// 정수, 문자열, 불리언은 값을 비교하고, 배열과 해시는 원소들을 재귀적으로 비교한다. (해시는 순서와 상관없이 같은 키-값 쌍을 가지면 같다)
+// Comparable 객체는 Compare 의 결과로 비교하고, 함수를 비롯한 그 밖의 객체는 같은 객체인지 비교한다.
+func Equal(a, b Object) bool {
+	switch a := a.(type) {
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+	case *Integer:
+		b, ok := b.(*Integer)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || a.Len() != b.Len() {
+			return false
+		}
+		for _, pair := range a.pairs {
+			value, ok := b.Get(pair.Key.(Hashable))
+			if !ok || !Equal(pair.Value, value) {
+				return false
+			}
+		}
+		return true
+	case Comparable:
+		result, ok := a.Compare(b)
+		return ok && result == 0
+	}
+
+	return a == b
+}
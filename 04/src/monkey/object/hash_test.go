@@ -0,0 +1,185 @@
+package object
+
+import "testing"
+
+// collideStrings 는 테스트가 끝날 때까지 모든 문자열의 HashKey 가 같도록 StringHash 를 바꾼다.
+func collideStrings(t *testing.T) {
+	original := StringHash
+	StringHash = func(string) uint64 { return 42 }
+	t.Cleanup(func() { StringHash = original })
+}
+
+func TestHashPreservesInsertionOrder(t *testing.T) {
+	hash := &Hash{}
+	hash.Set(&String{Value: "c"}, &Integer{Value: 1})
+	hash.Set(&Integer{Value: 2}, &Integer{Value: 2})
+	hash.Set(TRUE, &Integer{Value: 3})
+	hash.Set(&String{Value: "a"}, &Integer{Value: 4})
+
+	// 이미 있는 키에 값을 저장하면 위치는 그대로이다
+	hash.Set(&String{Value: "c"}, &Integer{Value: 5})
+
+	if hash.Inspect() != "{c: 5, 2: 2, true: 3, a: 4}" {
+		t.Errorf("hash.Inspect() wrong. got=%q", hash.Inspect())
+	}
+
+	pairs := hash.Pairs()
+	if !hash.Delete(&Integer{Value: 2}) {
+		t.Errorf("hash.Delete did not find key 2")
+	}
+	if hash.Delete(&Integer{Value: 2}) {
+		t.Errorf("hash.Delete found deleted key 2")
+	}
+	if hash.Inspect() != "{c: 5, true: 3, a: 4}" {
+		t.Errorf("hash.Inspect() after Delete wrong. got=%q", hash.Inspect())
+	}
+	if len(pairs) != 4 || pairs[1].Key.Inspect() != "2" {
+		t.Errorf("Delete changed previously returned pairs. got=%v", pairs)
+	}
+
+	// Delete 이후에도 뒤에 있던 키들을 찾을 수 있다
+	value, ok := hash.Get(&String{Value: "a"})
+	if !ok || value.Inspect() != "4" {
+		t.Errorf("hash.Get(a) wrong. got=%v, %t", value, ok)
+	}
+	if _, ok := hash.Get(&Integer{Value: 2}); ok {
+		t.Errorf("hash.Get found deleted key 2")
+	}
+	if hash.Len() != 3 {
+		t.Errorf("hash.Len() wrong. got=%d", hash.Len())
+	}
+}
+
+func TestHashKeyCollision(t *testing.T) {
+	collideStrings(t)
+
+	a := &String{Value: "a"}
+	b := &String{Value: "b"}
+	if a.HashKey() != b.HashKey() {
+		t.Fatalf("StringHash was not replaced")
+	}
+
+	hash := NewHash()
+	hash.Set(a, &Integer{Value: 1})
+	hash.Set(b, &Integer{Value: 2})
+	hash.Set(&String{Value: "c"}, &Integer{Value: 3})
+
+	if hash.Len() != 3 {
+		t.Fatalf("colliding keys overwrote each other. got=%s", hash.Inspect())
+	}
+
+	tests := []struct {
+		key      string
+		expected int64
+	}{
+		{"a", 1},
+		{"b", 2},
+		{"c", 3},
+	}
+	for _, tt := range tests {
+		value, ok := hash.Get(&String{Value: tt.key})
+		if !ok {
+			t.Errorf("no value for key %q", tt.key)
+			continue
+		}
+		if value.(*Integer).Value != tt.expected {
+			t.Errorf("wrong value for key %q. expected=%d, got=%s",
+				tt.key, tt.expected, value.Inspect())
+		}
+	}
+
+	if _, ok := hash.Get(&String{Value: "d"}); ok {
+		t.Errorf("found value for missing colliding key")
+	}
+
+	// 충돌하는 키 중 하나를 바꾸거나 지워도 다른 키에는 영향이 없다
+	hash.Set(&String{Value: "b"}, &Integer{Value: 20})
+	hash.Delete(&String{Value: "a"})
+	if hash.Inspect() != "{b: 20, c: 3}" {
+		t.Errorf("hash.Inspect() wrong. got=%q", hash.Inspect())
+	}
+	if value, ok := hash.Get(&String{Value: "c"}); !ok || value.(*Integer).Value != 3 {
+		t.Errorf("wrong value for key %q after Delete. got=%v", "c", value)
+	}
+}
+
+func TestKeysEqual(t *testing.T) {
+	tests := []struct {
+		a, b     Object
+		expected bool
+	}{
+		{&String{Value: "a"}, &String{Value: "a"}, true},
+		{&String{Value: "a"}, &String{Value: "b"}, false},
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &String{Value: "1"}, false},
+		{TRUE, &Boolean{Value: true}, true},
+		{TRUE, FALSE, false},
+	}
+
+	for _, tt := range tests {
+		if got := KeysEqual(tt.a, tt.b); got != tt.expected {
+			t.Errorf("KeysEqual(%s, %s) wrong. expected=%t, got=%t",
+				tt.a.Inspect(), tt.b.Inspect(), tt.expected, got)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	fn := &Builtin{}
+	hash := func(pairs ...Object) *Hash {
+		h := NewHash()
+		for i := 0; i < len(pairs); i += 2 {
+			h.Set(pairs[i].(Hashable), pairs[i+1])
+		}
+		return h
+	}
+	array := func(elements ...Object) *Array { return &Array{Elements: elements} }
+
+	tests := []struct {
+		a, b     Object
+		expected bool
+	}{
+		{array(&Integer{Value: 1}, &String{Value: "a"}), array(&Integer{Value: 1}, &String{Value: "a"}), true},
+		{array(&Integer{Value: 1}), array(&Integer{Value: 2}), false},
+		{array(array(TRUE)), array(array(TRUE)), true},
+		{array(), array(NULL), false},
+		{
+			hash(&String{Value: "a"}, &Integer{Value: 1}, &String{Value: "b"}, array()),
+			hash(&String{Value: "b"}, array(), &String{Value: "a"}, &Integer{Value: 1}),
+			true,
+		},
+		{hash(&String{Value: "a"}, &Integer{Value: 1}), hash(&String{Value: "a"}, &Integer{Value: 2}), false},
+		{hash(), array(), false},
+		{fn, fn, true},
+		{fn, &Builtin{}, false},
+		{NULL, NULL, true},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Equal(%s, %s) wrong. expected=%t, got=%t",
+				tt.a.Inspect(), tt.b.Inspect(), tt.expected, got)
+		}
+	}
+}
+
+func TestArrayHashKey(t *testing.T) {
+	a := &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}}
+	b := &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}}
+	c := &Array{Elements: []Object{&String{Value: "a"}, &Integer{Value: 1}}}
+
+	if a.HashKey() != b.HashKey() {
+		t.Errorf("arrays with same elements have different hash keys")
+	}
+	if a.HashKey() == c.HashKey() {
+		t.Errorf("arrays with different elements have same hash keys")
+	}
+
+	if _, ok := AsHashable(a); !ok {
+		t.Errorf("array of hashable elements is not hashable")
+	}
+	nested := &Array{Elements: []Object{a, &Array{Elements: []Object{&Builtin{}}}}}
+	if _, ok := AsHashable(nested); ok {
+		t.Errorf("array containing a function is hashable")
+	}
+}
@@ -0,0 +1,110 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 호스트(Go 코드)가 정의한 객체 타입은 아래 인터페이스를 구현하여 평가기의 연산에 참여할 수 있다.
+// 평가기는 내장 타입으로 처리할 수 없는 피연산자를 만났을 때 이 인터페이스들을 확인한다.
+
+// Indexable 은 obj[key] 인덱스 연산을 지원하는 객체이다.
+// Index 는 키에 해당하는 값이 없다면 nil 이나 NULL 을, 키를 사용할 수 없다면 *Error 를 반환한다.
+type Indexable interface {
+	Object
+	Index(key Object) Object
+}
+
+// Callable 은 obj(args) 처럼 함수로 호출할 수 있는 객체이다.
+type Callable interface {
+	Object
+	Call(args ...Object) Object
+}
+
+// Comparable 은 ==, !=, <, > 연산을 지원하는 객체이다.
+// Compare 는 other 보다 작으면 음수, 같으면 0, 크면 양수를 반환하고,
+// other 와 비교할 수 없다면 ok 로 false 를 반환한다.
+type Comparable interface {
+	Object
+	Compare(other Object) (result int, ok bool)
+}
+
+// Method 는 Native 객체의 메서드로, 호출될 때 자신을 가진 객체를 self 로 전달받는다.
+type Method func(self *Native, args ...Object) Object
+
+// Native 는 Go 값을 감싸 스크립트에 속성과 메서드를 노출하는 범용 호스트 객체이다.
+// obj["name"] 은 Properties 에서 값을, 없다면 Methods 에서 self 가 바인딩된 Builtin 을 찾는다.
+//
+//	db := &object.Native{
+//		TypeName: "DB",
+//		Value:    conn,
+//		Methods: map[string]object.Method{
+//			"query": func(self *object.Native, args ...object.Object) object.Object { ... },
+//		},
+//	}
+type Native struct {
+	TypeName   ObjectType
+	Value      interface{}
+	Properties map[string]Object
+	Methods    map[string]Method
+}
+
+func (n *Native) Type() ObjectType { return n.TypeName }
+func (n *Native) Inspect() string {
+	if s, ok := n.Value.(fmt.Stringer); ok {
+		return fmt.Sprintf("<%s %s>", n.TypeName, s.String())
+	}
+
+	names := make([]string, 0, len(n.Properties))
+	for name := range n.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	props := make([]string, 0, len(names))
+	for _, name := range names {
+		props = append(props, name+": "+n.Properties[name].Inspect())
+	}
+
+	return fmt.Sprintf("<%s {%s}>", n.TypeName, strings.Join(props, ", "))
+}
+
+func (n *Native) Index(key Object) Object {
+	name, ok := key.(*String)
+	if !ok {
+		return &Error{Message: fmt.Sprintf("unusable as %s key: %s", n.TypeName, key.Type())}
+	}
+
+	if prop, ok := n.Properties[name.Value]; ok {
+		return prop
+	}
+
+	if method, ok := n.Methods[name.Value]; ok {
+		return &Builtin{Fn: func(args ...Object) Object {
+			return method(n, args...)
+		}}
+	}
+
+	return NULL
+}
+
+// Compare 는 같은 타입이면서 감싼 Go 값이 같은 Native 끼리만 같다고 판단한다.
+// 대소 비교가 필요한 타입은 Comparable 을 직접 구현해야 한다.
+func (n *Native) Compare(other Object) (int, bool) {
+	o, ok := other.(*Native)
+	if !ok || o.TypeName != n.TypeName {
+		return 0, false
+	}
+	if n == o {
+		return 0, true
+	}
+	// 맵이나 슬라이스처럼 비교할 수 없는 Go 값을 == 로 비교하면 panic 이 발생한다.
+	// interface{} 필드가 슬라이스를 담은 구조체처럼 타입은 비교할 수 있어도 값은 비교할 수 없는 경우가 있으므로 값으로 확인한다
+	if n.Value != nil && reflect.ValueOf(n.Value).Comparable() && reflect.ValueOf(o.Value).Comparable() &&
+		n.Value == o.Value {
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package object
+
+import "testing"
+
+type stringerValue struct{}
+
+func (stringerValue) String() string { return "handle#1" }
+
+func TestNativeInspect(t *testing.T) {
+	tests := []struct {
+		native   *Native
+		expected string
+	}{
+		{&Native{TypeName: "DB"}, "<DB {}>"},
+		{
+			&Native{TypeName: "USER", Properties: map[string]Object{
+				"name": &String{Value: "kim"},
+				"age":  &Integer{Value: 30},
+			}},
+			"<USER {age: 30, name: kim}>",
+		},
+		{&Native{TypeName: "CONN", Value: stringerValue{}}, "<CONN handle#1>"},
+	}
+
+	for _, tt := range tests {
+		if tt.native.Inspect() != tt.expected {
+			t.Errorf("Inspect wrong. expected=%q, got=%q",
+				tt.expected, tt.native.Inspect())
+		}
+	}
+}
+
+func TestNativeIndex(t *testing.T) {
+	native := &Native{
+		TypeName:   "USER",
+		Value:      "kim",
+		Properties: map[string]Object{"age": &Integer{Value: 30}},
+		Methods: map[string]Method{
+			"greet": func(self *Native, args ...Object) Object {
+				return &String{Value: "hello " + self.Value.(string)}
+			},
+		},
+	}
+
+	if native.Index(&String{Value: "age"}).Inspect() != "30" {
+		t.Errorf("property lookup failed")
+	}
+
+	method, ok := native.Index(&String{Value: "greet"}).(*Builtin)
+	if !ok {
+		t.Fatalf("method is not Builtin")
+	}
+	if method.Fn().Inspect() != "hello kim" {
+		t.Errorf("method call wrong. got=%q", method.Fn().Inspect())
+	}
+
+	if native.Index(&String{Value: "missing"}) != NULL {
+		t.Errorf("missing key is not NULL")
+	}
+
+	testErrorMessage(t, native.Index(TRUE), "unusable as USER key: BOOLEAN")
+}
+
+func TestNativeCompare(t *testing.T) {
+	a := &Native{TypeName: "T", Value: 1}
+	b := &Native{TypeName: "T", Value: 1}
+	c := &Native{TypeName: "T", Value: 2}
+	d := &Native{TypeName: "U", Value: 1}
+	m := &Native{TypeName: "T", Value: map[string]int{}}
+
+	if _, ok := a.Compare(b); !ok {
+		t.Errorf("natives with equal values are not equal")
+	}
+	if _, ok := a.Compare(c); ok {
+		t.Errorf("natives with different values are equal")
+	}
+	if _, ok := a.Compare(d); ok {
+		t.Errorf("natives with different types are equal")
+	}
+	if _, ok := m.Compare(&Native{TypeName: "T", Value: map[string]int{}}); ok {
+		t.Errorf("natives with uncomparable values are equal")
+	}
+	if _, ok := m.Compare(m); !ok {
+		t.Errorf("native is not equal to itself")
+	}
+
+	// 타입은 비교할 수 있지만 interface{} 필드에 슬라이스를 담은 값
+	type box struct{ v interface{} }
+	s := &Native{TypeName: "T", Value: box{[]int{1}}}
+	if _, ok := s.Compare(&Native{TypeName: "T", Value: box{[]int{1}}}); ok {
+		t.Errorf("natives with uncomparable values are equal")
+	}
+	if _, ok := a.Compare(&Native{TypeName: "T", Value: box{1}}); ok {
+		t.Errorf("natives with different Go types are equal")
+	}
+	if _, ok := (&Native{TypeName: "T", Value: box{1}}).Compare(s); ok {
+		t.Errorf("natives with different values are equal")
+	}
+}
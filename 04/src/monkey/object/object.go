@@ -2,9 +2,11 @@ package object
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
 	"monkey/ast"
+	"monkey/token"
 	"strings"
 )
 
@@ -12,6 +14,14 @@ import (
 // 한 개 이상의 Object 를 받아 하나의 반환값으로서의 Object 를 반환하는 함수
 type BuiltinFunction func(args ...Object) Object
 
+// Applier 는 내장 함수가 인자로 받은 함수 객체를 호출할 수 있도록 평가기가 구현하는 인터페이스
+type Applier interface {
+	Apply(fn Object, args ...Object) Object
+}
+
+// CallbackFunction 은 Applier 를 통해 Monkey 함수를 다시 호출(callback)할 수 있는 내장 함수를 표현하는 타입
+type CallbackFunction func(applier Applier, args ...Object) Object
+
 type ObjectType string
 
 const (
@@ -27,10 +37,29 @@ const (
 	FUNCTION_OBJ = "FUNCTION"
 	BUILTIN_OBJ  = "BUILTIN"
 
+	TASK_OBJ    = "TASK"
+	CHANNEL_OBJ = "CHANNEL"
+
 	ARRAY_OBJ = "ARRAY" // ArrayLiteral 을 평가하기 위한 객체
 	HASH_OBJ  = "HASH"
 )
 
+// NULL, TRUE, FALSE 는 평가기 전체에서 공유하는 싱글턴 객체이다.
+// 평가기는 참/거짓과 null 을 포인터로 비교하므로 새로운 Boolean 이나 Null 을 만들지 말고 이 값들을 사용해야 한다.
+var (
+	NULL  = &Null{}
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
+)
+
+// NativeBoolToBoolean 은 Go 의 bool 을 TRUE 또는 FALSE 싱글턴으로 변환한다.
+func NativeBoolToBoolean(input bool) *Boolean {
+	if input {
+		return TRUE
+	}
+	return FALSE
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -38,6 +67,7 @@ type HashKey struct {
 
 // Hashable 해시 리터럴이나 해시 인덱스 표현식을 평가할 때, 주어진 객체가 해시키로 적절한지 평가하기 위함
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
@@ -88,15 +118,27 @@ func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
 type Error struct {
 	Message string
+	// Cause 는 에러가 발생한 원인이 되는 Go 에러로, 실행 제한에 걸린 경우처럼 호스트가
+	// errors.Is 로 에러를 구분해야 할 때 설정된다. 일반적인 런타임 에러에서는 nil 이다.
+	Cause error
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
+// Error 와 Unwrap 메서드로 *Error 는 Go 의 error 인터페이스를 만족한다.
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Cause }
+
 type Function struct {
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
+	// Name 은 함수 리터럴이 let 으로 바인딩된 이름. 익명 함수라면 빈 문자열이다
+	Name string
+	// File 과 Pos 는 함수 리터럴이 정의된 파일과 fn 키워드의 위치. 파일 없이 평가했다면 File 은 빈 문자열이다
+	File string
+	Pos  token.Position
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -111,9 +153,8 @@ func (f *Function) Inspect() string {
 	out.WriteString("fn")
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") {\n")
+	out.WriteString(") ")
 	out.WriteString(f.Body.String())
-	out.WriteString("\n}")
 
 	return out.String()
 }
@@ -126,15 +167,14 @@ type String struct {
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return s.Value }
 func (s *String) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(s.Value))
-
-	return HashKey{Type: s.Type(), Value: h.Sum64()}
+	return HashKey{Type: s.Type(), Value: StringHash(s.Value)}
 }
 
 // Builtin 내장 함수를 표현하는 타입
 type Builtin struct {
 	Fn BuiltinFunction
+	// Callback 이 설정되어 있다면 평가기는 Fn 대신 Callback 을 호출한다
+	Callback CallbackFunction
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -146,6 +186,22 @@ type Array struct {
 }
 
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
+
+// HashKey 는 원소들의 HashKey 로부터 배열의 HashKey 를 만든다.
+// 해시 키로 사용할 수 없는 원소가 있을 수 있으므로 AsHashable 로 확인한 뒤에 사용해야 한다.
+func (ao *Array) HashKey() HashKey {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	for _, e := range ao.Elements {
+		h.Write([]byte(e.Type()))
+		if e, ok := e.(Hashable); ok {
+			binary.LittleEndian.PutUint64(buf, e.HashKey().Value)
+			h.Write(buf)
+		}
+	}
+
+	return HashKey{Type: ao.Type(), Value: h.Sum64()}
+}
 func (ao *Array) Inspect() string {
 	var out bytes.Buffer
 
@@ -161,27 +217,12 @@ func (ao *Array) Inspect() string {
 	return out.String()
 }
 
-// HashPair 맵 자료형의 값을 표현하는 객체
-// monkey 언에서 map 은 map[object.HashKey]object.HashPair 로 표현되는데,
-// key: value 가 아닌 hashkey: hashpair 로 표현하는 이유는
-// key 가 겉보기에는 같더라도 실제로는 메모리상 다른 주소를 가진 값일 수 있기 때문이다. 이 경우 제대로 value 를 찾아낼 수 없다.
-// 그렇기에 hashkey 를 통해 hashpair 를 찾고 겉보기의 key 값을 hashpair 에 저장한다.
-type HashPair struct {
-	Key   Object
-	Value Object
-}
-
-// Hash 해시 리터럴을 평가하기 위한 객체
-type Hash struct {
-	Pairs map[HashKey]HashPair
-}
-
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.pairs {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
 			pair.Key.Inspect(), pair.Value.Inspect()))
 	}
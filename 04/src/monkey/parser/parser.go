@@ -33,14 +33,23 @@ var precedences = map[token.TokenType]int{
 	token.LBRACKET: INDEX,
 }
 
+// Precedence 는 중위 연산자 토큰의 우선순위를 반환한다. 중위 연산자가 아니라면 LOWEST 를 반환한다.
+func Precedence(t token.TokenType) int {
+	if p, ok := precedences[t]; ok {
+		return p
+	}
+	return LOWEST
+}
+
 type (
 	prefixParseFn func() ast.Expression
 	infixParseFn  func(ast.Expression) ast.Expression
 )
 
 type Parser struct {
-	l      *lexer.Lexer
-	errors []string
+	l         *lexer.Lexer
+	errors    []string
+	positions []token.Position // errors 의 각 에러가 발생한 위치
 
 	curToken  token.Token
 	peekToken token.Token
@@ -68,6 +77,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral) // 왼쪽 대괄호를 만나서 배열 리터럴 파싱
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)    // 왼쪽 중괄호를 만나서 해시 리터럴 파싱
+	p.registerPrefix(token.IMPORT, p.parseImportExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -116,15 +126,39 @@ func (p *Parser) Errors() []string {
 	return p.errors
 }
 
+// Error 위치 정보를 가진 파싱 에러
+type Error struct {
+	Pos     token.Position
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Pos.String() + ": " + e.Message
+}
+
+// ErrorList 는 Errors 와 같은 에러들을 에러가 발생한 토큰의 위치와 함께 반환한다.
+func (p *Parser) ErrorList() []Error {
+	list := make([]Error, len(p.errors))
+	for i, msg := range p.errors {
+		list[i] = Error{Pos: p.positions[i], Message: msg}
+	}
+	return list
+}
+
+func (p *Parser) addError(pos token.Position, msg string) {
+	p.errors = append(p.errors, msg)
+	p.positions = append(p.positions, pos)
+}
+
 func (p *Parser) peekError(t token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
 		t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(p.peekToken.Pos, msg)
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.addError(p.curToken.Pos, msg)
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
@@ -145,7 +179,11 @@ func (p *Parser) ParseProgram() *ast.Program {
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		// 파싱에 실패한 let 문이 nil 이 아닌 인터페이스 값으로 프로그램에 추가되지 않게 한다
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN:
 		return p.parseReturnStatement()
 	default:
@@ -169,6 +207,10 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	p.nextToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
+	// 호출 스택에 보여줄 수 있도록 함수에 바인딩되는 이름을 기록한다
+	if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+		fn.Name = stmt.Name.Value
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
@@ -251,7 +293,7 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addError(p.curToken.Pos, msg)
 		return nil
 	}
 
@@ -445,10 +487,31 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 
 // parseIndexExpression 함수는 왼쪽 대괄호를 만나면 호출되며, 인덱스 연산을 파싱한다.
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	tok := p.curToken // '['
 
-	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	// 대괄호 안에 콜론이 나오면 인덱스가 아닌 구간(slice) 표현식이다.
+	var start ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		start = p.parseExpression(LOWEST)
+
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: tok, Left: left, Index: start}
+		}
+	}
+
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: start}
+	p.nextToken() // ':'
+
+	exp.End = p.parseSliceBound()
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		exp.Step = p.parseSliceBound()
+	}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
@@ -457,11 +520,21 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+// parseSliceBound 함수는 구간 표현식에서 콜론 뒤의 값을 파싱한다. 값이 생략되었다면 nil 을 반환한다.
+func (p *Parser) parseSliceBound() ast.Expression {
+	if p.peekTokenIs(token.COLON) || p.peekTokenIs(token.RBRACKET) {
+		return nil
+	}
+
+	p.nextToken()
+	return p.parseExpression(LOWEST)
+}
+
 // parseHashLiteral 함수는 왼쪽 중괄호를 만나면 호출되며, 해시 리터럴을 파싱한다.
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken} // '{'
-	// 해시 리터럴은 키와 값의 쌍으로 이루어져 있으므로, 키와 값의 쌍을 저장할 수 있는 맵을 만든다.
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	// 해시 리터럴은 키와 값의 쌍으로 이루어져 있으므로, 키와 값의 쌍을 나온 순서대로 저장한다.
+	hash.Pairs = []ast.HashLiteralPair{}
 
 	// 해시 맵이 끝났음을 나타내는 오른쪽 중괄호가 나올 때까지 반복한다.
 	for !p.peekTokenIs(token.RBRACE) {
@@ -476,7 +549,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs, ast.HashLiteralPair{Key: key, Value: value})
 
 		// value 를 파싱한 뒤에는 중괄호가 나오거나 반점이 나와야한다. 그렇지 않다면 바로 nil 반환
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
@@ -491,6 +564,20 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+// parseImportExpression 함수는 import 키워드를 만나면 호출되며, 불러올 모듈의 경로를 파싱한다.
+func (p *Parser) parseImportExpression() ast.Expression {
+	exp := &ast.ImportExpression{Token: p.curToken}
+
+	// 모듈의 경로는 문자열 리터럴로만 지정할 수 있다.
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	exp.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
@@ -275,7 +275,7 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		},
 		{
 			"3 + 4; -5 * 5",
-			"(3 + 4)((-5) * 5)",
+			"(3 + 4); ((-5) * 5)",
 		},
 		{
 			"5 > 4 == 3 < 4",
@@ -349,6 +349,10 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a * b[1:c + 1][::-1]",
+			"(a * ((b[1:(c + 1)])[::(-1)]))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -697,6 +701,45 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestImportExpression(t *testing.T) {
+	input := `let math = import "lib/math.mk";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.LetStatement)
+	imp, ok := stmt.Value.(*ast.ImportExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.ImportExpression. got=%T", stmt.Value)
+	}
+
+	if imp.Path.Value != "lib/math.mk" {
+		t.Errorf("imp.Path.Value not %q. got=%q", "lib/math.mk", imp.Path.Value)
+	}
+
+	if imp.String() != `import "lib/math.mk"` {
+		t.Errorf("imp.String() wrong. got=%q", imp.String())
+	}
+}
+
+func TestImportExpressionRequiresString(t *testing.T) {
+	l := lexer.New("import math;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 {
+		t.Fatalf("parser did not report an error")
+	}
+
+	expected := "expected next token to be STRING, got IDENT instead"
+	if errors[0] != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, errors[0])
+	}
+}
+
 func TestParsingEmptyArrayLiterals(t *testing.T) {
 	input := "[]"
 
@@ -762,6 +805,83 @@ func TestParsingIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		start    interface{}
+		end      interface{}
+		step     interface{}
+		expected string
+	}{
+		{"myArray[1:3]", 1, 3, nil, "(myArray[1:3])"},
+		{"myArray[:3]", nil, 3, nil, "(myArray[:3])"},
+		{"myArray[1:]", 1, nil, nil, "(myArray[1:])"},
+		{"myArray[:]", nil, nil, nil, "(myArray[:])"},
+		{"myArray[1:5:2]", 1, 5, 2, "(myArray[1:5:2])"},
+		{"myArray[::2]", nil, nil, 2, "(myArray[::2])"},
+		{"myArray[::]", nil, nil, nil, "(myArray[:])"},
+		{"myArray[a:b]", "a", "b", nil, "(myArray[a:b])"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		sliceExp, ok := stmt.Expression.(*ast.SliceExpression)
+		if !ok {
+			t.Fatalf("exp not *ast.SliceExpression. got=%T", stmt.Expression)
+		}
+
+		if !testIdentifier(t, sliceExp.Left, "myArray") {
+			return
+		}
+
+		bounds := []struct {
+			exp      ast.Expression
+			expected interface{}
+		}{
+			{sliceExp.Start, tt.start},
+			{sliceExp.End, tt.end},
+			{sliceExp.Step, tt.step},
+		}
+		for _, bound := range bounds {
+			if bound.expected == nil {
+				if bound.exp != nil {
+					t.Errorf("bound is not nil for %q. got=%s", tt.input, bound.exp)
+				}
+				continue
+			}
+			testLiteralExpression(t, bound.exp, bound.expected)
+		}
+
+		if sliceExp.String() != tt.expected {
+			t.Errorf("sliceExp.String() wrong. expected=%q, got=%q",
+				tt.expected, sliceExp.String())
+		}
+	}
+}
+
+func TestParsingSliceExpressionErrors(t *testing.T) {
+	tests := []string{
+		"a[1:2:3:4]",
+		"a[1:2",
+		"a[1 2]",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("parser did not report an error for %q", input)
+		}
+	}
+}
+
 func TestParsingEmptyHashLiteral(t *testing.T) {
 	input := "{}"
 
@@ -805,14 +925,15 @@ func TestParsingHashLiteralsStringKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		literal, ok := key.(*ast.StringLiteral)
 		if !ok {
 			t.Errorf("key is not ast.StringLiteral. got=%T", key)
 			continue
 		}
 
-		expectedValue := expected[literal.String()]
+		expectedValue := expected[literal.Value]
 		testIntegerLiteral(t, value, expectedValue)
 	}
 }
@@ -840,7 +961,8 @@ func TestParsingHashLiteralsBooleanKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		boolean, ok := key.(*ast.Boolean)
 		if !ok {
 			t.Errorf("key is not ast.BooleanLiteral. got=%T", key)
@@ -876,7 +998,8 @@ func TestParsingHashLiteralsIntegerKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		integer, ok := key.(*ast.IntegerLiteral)
 		if !ok {
 			t.Errorf("key is not ast.IntegerLiteral. got=%T", key)
@@ -919,16 +1042,17 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 		},
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		literal, ok := key.(*ast.StringLiteral)
 		if !ok {
 			t.Errorf("key is not ast.StringLiteral. got=%T", key)
 			continue
 		}
 
-		testFunc, ok := tests[literal.String()]
+		testFunc, ok := tests[literal.Value]
 		if !ok {
-			t.Errorf("No test function for key %q found", literal.String())
+			t.Errorf("No test function for key %q found", literal.Value)
 			continue
 		}
 
@@ -936,6 +1060,38 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingHashLiteralsPreservesOrder(t *testing.T) {
+	input := `{"c": 1, "a": 2, "b": 3, "a": 4}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	// 중복된 키도 소스 코드에 나온 순서대로 모두 저장한다
+	expected := []string{`"c"`, `"a"`, `"b"`, `"a"`}
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+	for i, key := range expected {
+		if hash.Pairs[i].Key.String() != key {
+			t.Errorf("hash.Pairs[%d] has wrong key. expected=%q, got=%q",
+				i, key, hash.Pairs[i].Key.String())
+		}
+		testIntegerLiteral(t, hash.Pairs[i].Value, int64(i+1))
+	}
+
+	if hash.String() != `{"c":1, "a":2, "b":3, "a":4}` {
+		t.Errorf("hash.String() wrong. got=%q", hash.String())
+	}
+}
+
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 	if s.TokenLiteral() != "let" {
 		t.Errorf("s.TokenLiteral not 'let'. got=%q", s.TokenLiteral())
@@ -1080,3 +1236,26 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+func TestErrorPositions(t *testing.T) {
+	input := "let x = 5;\nlet = 10;\nlet y = ;"
+
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	expected := []string{
+		"2:5: expected next token to be IDENT, got = instead",
+		"2:5: no prefix parse function for = found",
+		"3:9: no prefix parse function for ; found",
+	}
+	errors := p.ErrorList()
+	if len(errors) != len(expected) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%d (%v)",
+			len(expected), len(errors), p.Errors())
+	}
+	for i, err := range errors {
+		if err.Error() != expected[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expected[i], err.Error())
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"monkey/lexer"
+	"monkey/token"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// TestStringRoundTrip 은 parser_test.go 의 모든 문자열 리터럴 중 파싱에 성공하는 입력에 대해
+// program.String() 을 다시 파싱하면 토큰을 제외하고 같은 AST 가 되는지 확인한다.
+func TestStringRoundTrip(t *testing.T) {
+	inputs := corpus(t, "parser_test.go")
+
+	checked := 0
+	for _, input := range inputs {
+		p := New(lexer.New(input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 || len(program.Statements) == 0 {
+			continue
+		}
+		checked++
+
+		source := program.String()
+		p2 := New(lexer.New(source))
+		reparsed := p2.ParseProgram()
+		if len(p2.Errors()) != 0 {
+			t.Errorf("String() of %q is not valid source %q: %v",
+				input, source, p2.Errors())
+			continue
+		}
+
+		if !equalIgnoringTokens(reflect.ValueOf(program), reflect.ValueOf(reparsed)) {
+			t.Errorf("String() of %q parses to a different program. source=%q, got=%q",
+				input, source, reparsed.String())
+		}
+	}
+
+	if checked == 0 {
+		t.Fatal("no parseable inputs found in corpus")
+	}
+}
+
+// corpus 는 Go 소스 파일 filename 의 모든 문자열 리터럴을 반환한다
+func corpus(t *testing.T, filename string) []string {
+	t.Helper()
+
+	file, err := goparser.ParseFile(gotoken.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inputs []string
+	goast.Inspect(file, func(n goast.Node) bool {
+		lit, ok := n.(*goast.BasicLit)
+		if !ok || lit.Kind != gotoken.STRING {
+			return true
+		}
+		if s, err := strconv.Unquote(lit.Value); err == nil {
+			inputs = append(inputs, s)
+		}
+		return true
+	})
+	return inputs
+}
+
+var tokenType = reflect.TypeOf(token.Token{})
+
+// equalIgnoringTokens 는 두 AST 가 token.Token 필드를 제외하고 같은지 비교한다.
+// 괄호로 묶인 표현식처럼 같은 노드가 다른 토큰에서 시작할 수 있기 때문이다.
+func equalIgnoringTokens(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalIgnoringTokens(a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		if a.Type() == tokenType {
+			return true
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if !equalIgnoringTokens(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalIgnoringTokens(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
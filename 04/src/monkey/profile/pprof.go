@@ -0,0 +1,153 @@
+package profile
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// pprof 형식은 github.com/google/pprof/proto/profile.proto 의 Profile 메시지를 gzip 으로 압축한 것이다.
+// 외부 의존성 없이 필요한 필드만 직접 인코딩한다.
+
+// Profile 메시지의 필드 번호
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profileDurationNanos     = 10
+	profileDefaultSampleType = 14
+)
+
+// WritePprof 는 go tool pprof 가 읽을 수 있는 형식으로 쓴다.
+// 호출 경로마다 calls(호출 횟수)와 time(경로의 마지막 함수의 Exclusive 시간) 두 값을 가진 샘플을 만든다.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	table := newStringTable()
+	var b protobuf
+
+	for _, st := range [][2]string{{"calls", "count"}, {"time", "nanoseconds"}} {
+		b.message(profileSampleType, func(vt *protobuf) {
+			vt.int64(1, table.index(st[0]))
+			vt.int64(2, table.index(st[1]))
+		})
+	}
+
+	var ids []int64
+	p.walkStacks(func(n *node, frames []*Function, labels []string) error {
+		b.message(profileSample, func(sample *protobuf) {
+			// 위치는 가장 안쪽의 함수부터 적는다
+			ids = ids[:0]
+			for i := len(frames) - 1; i >= 0; i-- {
+				ids = append(ids, int64(frames[i].id))
+			}
+			sample.packed(1, ids)
+			sample.packed(2, []int64{n.calls, n.time.Nanoseconds()})
+		})
+		return nil
+	})
+
+	// 함수마다 같은 id 의 Location 과 Function 을 하나씩 만든다
+	for _, fn := range p.ordered {
+		fn := fn
+		b.message(profileLocation, func(loc *protobuf) {
+			loc.int64(1, int64(fn.id))
+			loc.message(4, func(line *protobuf) {
+				line.int64(1, int64(fn.id))
+				line.int64(2, int64(fn.Pos.Line))
+			})
+		})
+		// pprof 는 이름의 <...> 를 C++ 템플릿 인자로 보고 지우므로 <main> 은 main 으로 쓴다
+		name := strings.Trim(fn.Name, "<>")
+		b.message(profileFunction, func(f *protobuf) {
+			f.int64(1, int64(fn.id))
+			f.int64(2, table.index(name))
+			f.int64(3, table.index(name))
+			f.int64(4, table.index(fn.File))
+			f.int64(5, int64(fn.Pos.Line))
+		})
+	}
+
+	b.int64(profileDurationNanos, p.duration.Nanoseconds())
+	b.int64(profileDefaultSampleType, table.index("time"))
+	// 문자열 테이블은 모든 문자열을 등록한 뒤에 쓴다. 0 번은 항상 빈 문자열이다
+	for _, s := range table.strings {
+		b.bytes(profileStringTable, []byte(s))
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.buf); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+type stringTable struct {
+	strings []string
+	indices map[string]int64
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{strings: []string{""}, indices: map[string]int64{"": 0}}
+}
+
+func (t *stringTable) index(s string) int64 {
+	i, ok := t.indices[s]
+	if !ok {
+		i = int64(len(t.strings))
+		t.strings = append(t.strings, s)
+		t.indices[s] = i
+	}
+	return i
+}
+
+// protobuf 는 protocol buffers 의 varint 와 length-delimited 필드만 인코딩한다
+type protobuf struct {
+	buf []byte
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.buf = append(b.buf, byte(x)|0x80)
+		x >>= 7
+	}
+	b.buf = append(b.buf, byte(x))
+}
+
+func (b *protobuf) key(field int, wire int) {
+	b.varint(uint64(field)<<3 | uint64(wire))
+}
+
+// int64 는 값이 0 이면 proto3 의 기본값이므로 쓰지 않는다
+func (b *protobuf) int64(field int, x int64) {
+	if x == 0 {
+		return
+	}
+	b.key(field, wireVarint)
+	b.varint(uint64(x))
+}
+
+func (b *protobuf) bytes(field int, data []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(data)))
+	b.buf = append(b.buf, data...)
+}
+
+func (b *protobuf) packed(field int, xs []int64) {
+	var inner protobuf
+	for _, x := range xs {
+		inner.varint(uint64(x))
+	}
+	b.bytes(field, inner.buf)
+}
+
+func (b *protobuf) message(field int, encode func(*protobuf)) {
+	var inner protobuf
+	encode(&inner)
+	b.bytes(field, inner.buf)
+}
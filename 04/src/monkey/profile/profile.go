@@ -0,0 +1,297 @@
+// Package profile 은 evaluator.Hook 으로 함수 호출을 관찰하는 프로파일러를 제공한다.
+//
+// Profiler 는 사용자 정의 함수마다 호출 횟수와 포함(inclusive)/제외(exclusive) 시간을 기록한다.
+// 함수는 정의된 위치와 let 으로 바인딩된 이름으로 구분한다. 결과는 표 형태의 보고서,
+// flame graph 도구가 읽는 collapsed stack 형식, go tool pprof 가 읽는 pprof 형식으로 쓸 수 있다.
+package profile
+
+import (
+	"fmt"
+	"io"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/object"
+	"monkey/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Function 은 함수 하나의 프로파일 결과이다.
+type Function struct {
+	// Name 은 함수의 이름. 가장 바깥의 코드는 "<main>", 이름이 없는 함수는 "<anonymous>" 이다
+	Name string
+	// File 과 Pos 는 함수가 정의된 위치이다
+	File string
+	Pos  token.Position
+
+	Calls int64
+	// Inclusive 는 함수 안에서 보낸 시간으로, 재귀 호출은 가장 바깥의 호출만 센다
+	Inclusive time.Duration
+	// Exclusive 는 Inclusive 에서 다른 사용자 정의 함수를 호출하는 데 보낸 시간을 뺀 시간이다.
+	// 내장함수를 호출한 시간은 호출한 함수의 시간에 포함된다
+	Exclusive time.Duration
+
+	id     int
+	active int // 호출 스택에 있는 이 함수의 호출 수
+}
+
+// Label 은 보고서와 flame graph 에 보여줄 함수의 이름과 정의된 위치이다.
+func (f *Function) Label() string {
+	if f.Pos.Line == 0 {
+		return f.Name
+	}
+	if f.File == "" {
+		return fmt.Sprintf("%s (%s)", f.Name, f.Pos)
+	}
+	return fmt.Sprintf("%s (%s:%s)", f.Name, filepath.Base(f.File), f.Pos)
+}
+
+type funcKey struct {
+	name string
+	file string
+	pos  token.Position
+}
+
+// node 는 호출 경로들의 트라이의 노드로, 루트에서 이 노드까지의 경로로 호출된 함수들의 합계이다.
+// 경로를 문자열로 저장하지 않으므로 재귀가 깊어도 호출마다 드는 비용은 일정하다
+type node struct {
+	fn       *Function
+	children map[*Function]*node
+	calls    int64
+	time     time.Duration // 경로의 마지막 함수의 Exclusive 시간
+}
+
+func (n *node) child(fn *Function) *node {
+	c, ok := n.children[fn]
+	if !ok {
+		c = &node{fn: fn, children: map[*Function]*node{}}
+		n.children[fn] = c
+	}
+	return c
+}
+
+type activation struct {
+	fn        *Function
+	node      *node
+	start     time.Time
+	children  time.Duration
+	recursive bool
+}
+
+// Profiler 는 evaluator.Options.Hook 으로 설정하는 프로파일러이다.
+// 평가가 끝나면 Stop 을 호출한 뒤 결과를 읽는다. spawn 으로 만든 태스크는 프로파일링하지 않는다.
+type Profiler struct {
+	now func() time.Time
+
+	funcs   map[funcKey]*Function
+	ordered []*Function // id 순서
+	root    *node       // <main> 의 노드
+
+	main     *Function
+	active   []*activation
+	started  bool
+	stopped  bool
+	duration time.Duration
+}
+
+// New 는 프로파일러를 만든다. 측정은 첫 번째 문을 평가할 때 시작한다.
+func New() *Profiler {
+	p := &Profiler{
+		now:   time.Now,
+		funcs: map[funcKey]*Function{},
+	}
+	p.main = p.function(funcKey{name: "<main>"})
+	p.root = &node{fn: p.main, children: map[*Function]*node{}}
+	return p
+}
+
+func (p *Profiler) function(key funcKey) *Function {
+	fn, ok := p.funcs[key]
+	if !ok {
+		fn = &Function{Name: key.name, File: key.file, Pos: key.pos, id: len(p.ordered) + 1}
+		p.funcs[key] = fn
+		p.ordered = append(p.ordered, fn)
+	}
+	return fn
+}
+
+func (p *Profiler) start(now time.Time) {
+	if p.started {
+		return
+	}
+	p.started = true
+	p.push(p.main, now)
+}
+
+func (p *Profiler) push(fn *Function, now time.Time) {
+	n := p.root
+	if len(p.active) != 0 {
+		n = p.active[len(p.active)-1].node.child(fn)
+	}
+	p.active = append(p.active, &activation{fn: fn, node: n, start: now, recursive: fn.active > 0})
+	fn.active++
+	fn.Calls++
+}
+
+func (p *Profiler) pop(now time.Time) {
+	a := p.active[len(p.active)-1]
+	p.active = p.active[:len(p.active)-1]
+
+	elapsed := now.Sub(a.start)
+	exclusive := elapsed - a.children
+	a.fn.Exclusive += exclusive
+	if !a.recursive {
+		a.fn.Inclusive += elapsed
+	}
+	a.fn.active--
+	if len(p.active) != 0 {
+		p.active[len(p.active)-1].children += elapsed
+	}
+	a.node.calls++
+	a.node.time += exclusive
+}
+
+// Statement 는 evaluator.Hook 을 구현한다. 첫 번째 문에서 측정을 시작한다.
+func (p *Profiler) Statement(file string, stmt ast.Statement, env *object.Environment) error {
+	if !p.started {
+		p.start(p.now())
+	}
+	return nil
+}
+
+// Enter 는 evaluator.Hook 을 구현한다.
+func (p *Profiler) Enter(fn object.Object, args []object.Object) {
+	f, ok := fn.(*object.Function)
+	if !ok || p.stopped {
+		return
+	}
+	now := p.now()
+	p.start(now)
+
+	name := f.Name
+	if name == "" {
+		name = "<anonymous>"
+	}
+	p.push(p.function(funcKey{name: name, file: f.File, pos: f.Pos}), now)
+}
+
+// Exit 는 evaluator.Hook 을 구현한다.
+func (p *Profiler) Exit(fn object.Object, result object.Object) {
+	if _, ok := fn.(*object.Function); !ok || p.stopped || len(p.active) <= 1 {
+		return
+	}
+	p.pop(p.now())
+}
+
+// Fork 는 evaluator.ForkHook 을 구현한다. spawn 으로 만든 태스크는 프로파일링하지 않는다.
+func (p *Profiler) Fork() evaluator.Hook {
+	return nil
+}
+
+// Stop 은 측정을 끝낸다. 가장 바깥의 코드의 시간은 Stop 을 호출해야 기록된다.
+func (p *Profiler) Stop() {
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	if !p.started {
+		return
+	}
+	now := p.now()
+	p.duration = now.Sub(p.active[0].start)
+	// 에러로 평가가 중단되어도 Exit 는 모두 호출되지만, 남은 호출이 있다면 함께 끝낸다
+	for len(p.active) != 0 {
+		p.pop(now)
+	}
+}
+
+// Functions 는 호출된 함수들을 Exclusive 시간이 긴 순서로 반환한다.
+func (p *Profiler) Functions() []*Function {
+	var result []*Function
+	for _, fn := range p.ordered {
+		if fn.Calls != 0 {
+			result = append(result, fn)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Exclusive > result[j].Exclusive
+	})
+	return result
+}
+
+// WriteReport 는 함수마다 호출 횟수와 시간을 표로 쓴다.
+func (p *Profiler) WriteReport(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%10s %12s %7s %12s %7s  %s\n",
+		"calls", "inclusive", "%", "exclusive", "%", "function"); err != nil {
+		return err
+	}
+	for _, fn := range p.Functions() {
+		if _, err := fmt.Fprintf(w, "%10d %12s %7s %12s %7s  %s\n",
+			fn.Calls,
+			fn.Inclusive, p.percent(fn.Inclusive),
+			fn.Exclusive, p.percent(fn.Exclusive),
+			fn.Label()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Profiler) percent(d time.Duration) string {
+	if p.duration <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(d)*100/float64(p.duration))
+}
+
+// walkStacks 는 호출된 적이 있는 호출 경로마다 f 를 호출한다. frames 는 가장 바깥의 함수부터 경로의 함수들이고,
+// labels 는 그 함수들의 collapsed stack 형식의 이름이다. 두 슬라이스는 f 가 반환한 뒤에 재사용된다.
+// 경로는 트리를 깊이 우선으로 순회하는 순서이며, 형제 노드들은 이름 순서로 방문한다
+func (p *Profiler) walkStacks(f func(n *node, frames []*Function, labels []string) error) error {
+	names := make(map[*Function]string, len(p.ordered))
+	for _, fn := range p.ordered {
+		names[fn] = strings.ReplaceAll(fn.Label(), ";", ",")
+	}
+
+	var frames []*Function
+	var labels []string
+	var walk func(n *node) error
+	walk = func(n *node) error {
+		frames = append(frames, n.fn)
+		labels = append(labels, names[n.fn])
+		if n.calls != 0 {
+			if err := f(n, frames, labels); err != nil {
+				return err
+			}
+		}
+
+		children := make([]*node, 0, len(n.children))
+		for _, c := range n.children {
+			children = append(children, c)
+		}
+		sort.Slice(children, func(i, j int) bool {
+			return names[children[i].fn] < names[children[j].fn]
+		})
+		for _, c := range children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+
+		frames = frames[:len(frames)-1]
+		labels = labels[:len(labels)-1]
+		return nil
+	}
+	return walk(p.root)
+}
+
+// WriteCollapsed 는 flamegraph.pl 이나 speedscope 가 읽는 collapsed stack 형식으로 쓴다.
+// 한 줄이 하나의 호출 경로이며, 경로의 마지막 함수의 Exclusive 시간을 나노초 단위로 적는다.
+func (p *Profiler) WriteCollapsed(w io.Writer) error {
+	return p.walkStacks(func(n *node, frames []*Function, labels []string) error {
+		_, err := fmt.Fprintf(w, "%s %d\n", strings.Join(labels, ";"), n.time.Nanoseconds())
+		return err
+	})
+}
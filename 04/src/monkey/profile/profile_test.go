@@ -0,0 +1,212 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"strings"
+	"testing"
+	"time"
+)
+
+// profileEval 은 시계를 읽을 때마다 1ms 씩 흐르는 프로파일러로 input 을 평가한다
+func profileEval(t *testing.T, input string) *Profiler {
+	t.Helper()
+	p := New()
+	clock := time.Unix(0, 0)
+	p.now = func() time.Time {
+		now := clock
+		clock = clock.Add(time.Millisecond)
+		return now
+	}
+
+	l := parser.New(lexer.New(input))
+	program := l.ParseProgram()
+	if len(l.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", l.Errors())
+	}
+	evaluator.EvalContext(context.Background(), program, object.NewEnvironment(), evaluator.Options{Hook: p})
+	p.Stop()
+	return p
+}
+
+const nested = `let leaf = fn() { 1 };
+let mid = fn() { leaf() + len("ab") + leaf() };
+mid();`
+
+func TestProfiler(t *testing.T) {
+	p := profileEval(t, nested)
+
+	// 시계는 main 시작(0), mid 호출(1), leaf 호출과 반환(2, 3), leaf 호출과 반환(4, 5), mid 반환(6), Stop(7) 에서 읽힌다
+	expected := []struct {
+		label     string
+		calls     int64
+		inclusive time.Duration
+		exclusive time.Duration
+	}{
+		{"mid (2:11)", 1, 5 * time.Millisecond, 3 * time.Millisecond},
+		{"<main>", 1, 7 * time.Millisecond, 2 * time.Millisecond},
+		{"leaf (1:12)", 2, 2 * time.Millisecond, 2 * time.Millisecond},
+	}
+
+	functions := p.Functions()
+	if len(functions) != len(expected) {
+		t.Fatalf("wrong number of functions. got=%d", len(functions))
+	}
+	for i, tt := range expected {
+		fn := functions[i]
+		if fn.Label() != tt.label || fn.Calls != tt.calls || fn.Inclusive != tt.inclusive || fn.Exclusive != tt.exclusive {
+			t.Errorf("functions[%d] wrong. expected=%+v, got=%s %d %s %s",
+				i, tt, fn.Label(), fn.Calls, fn.Inclusive, fn.Exclusive)
+		}
+	}
+}
+
+func TestProfilerRecursion(t *testing.T) {
+	p := profileEval(t, `let f = fn(n) { if (n > 0) { f(n - 1) } else { 0 } }; f(2);`)
+
+	var f *Function
+	for _, fn := range p.Functions() {
+		if fn.Name == "f" {
+			f = fn
+		}
+	}
+	// f(2) 는 시계 1 에 호출되어 6 에 반환된다. 재귀 호출의 시간은 두 번 세지 않는다
+	if f.Calls != 3 || f.Inclusive != 5*time.Millisecond || f.Exclusive != 5*time.Millisecond {
+		t.Errorf("wrong profile of f. got=%d %s %s", f.Calls, f.Inclusive, f.Exclusive)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	p := profileEval(t, nested)
+
+	var out bytes.Buffer
+	if err := p.WriteReport(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := `     calls    inclusive       %    exclusive       %  function
+         1          5ms   71.4%          3ms   42.9%  mid (2:11)
+         1          7ms  100.0%          2ms   28.6%  <main>
+         2          2ms   28.6%          2ms   28.6%  leaf (1:12)
+`
+	if out.String() != expected {
+		t.Errorf("wrong report.\nexpected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestWriteCollapsed(t *testing.T) {
+	p := profileEval(t, nested)
+
+	var out bytes.Buffer
+	if err := p.WriteCollapsed(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<main> 2000000
+<main>;mid (2:11) 3000000
+<main>;mid (2:11);leaf (1:12) 2000000
+`
+	if out.String() != expected {
+		t.Errorf("wrong collapsed stacks.\nexpected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestWriteCollapsedRecursion(t *testing.T) {
+	p := profileEval(t, `let f = fn(n) { if (n > 0) { f(n - 1) } }; let g = fn() { 1 }; f(1000); g(); f(1)`)
+
+	var out bytes.Buffer
+	if err := p.WriteCollapsed(&out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	// <main>, 깊이 1 부터 1001 까지의 f, main 에서 호출한 g
+	if len(lines) != 1003 {
+		t.Fatalf("wrong number of stacks. got=%d", len(lines))
+	}
+
+	// 같은 호출 경로는 한 번만 나오고, 경로는 깊이 우선으로 형제들은 이름 순서로 나온다
+	if !strings.HasPrefix(lines[1], "<main>;f (1:9) ") || !strings.HasPrefix(lines[2], "<main>;f (1:9);f (1:9) ") {
+		t.Errorf("wrong order of stacks. got=%q", lines[:3])
+	}
+	if frames := strings.Count(lines[1001], ";") + 1; frames != 1002 {
+		t.Errorf("deepest stack has wrong number of frames. got=%d", frames)
+	}
+	if !strings.HasPrefix(lines[1002], "<main>;g (1:52) ") {
+		t.Errorf("wrong last stack. got=%q", lines[1002])
+	}
+}
+
+func TestWritePprof(t *testing.T) {
+	p := profileEval(t, nested)
+
+	var out bytes.Buffer
+	if err := p.WritePprof(&out); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&out)
+	if err != nil {
+		t.Fatalf("output is not gzip: %s", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 최상위 필드들을 읽어 샘플과 문자열 테이블을 확인한다
+	var samples int
+	var table []string
+	for len(data) > 0 {
+		key, n := readVarint(data)
+		data = data[n:]
+		field, wire := int(key>>3), key&7
+		if wire == wireVarint {
+			_, n = readVarint(data)
+			data = data[n:]
+			continue
+		}
+		length, n := readVarint(data)
+		value := data[n : n+int(length)]
+		data = data[n+int(length):]
+
+		switch field {
+		case profileSample:
+			samples++
+		case profileStringTable:
+			table = append(table, string(value))
+		}
+	}
+
+	if samples != 3 {
+		t.Errorf("wrong number of samples. got=%d", samples)
+	}
+	expected := `["" calls count time nanoseconds main mid leaf]`
+	if got := fmtStrings(table); got != expected {
+		t.Errorf("wrong string table. expected=%s, got=%s", expected, got)
+	}
+}
+
+func readVarint(data []byte) (uint64, int) {
+	var x uint64
+	for i, b := range data {
+		x |= uint64(b&0x7f) << (7 * i)
+		if b < 0x80 {
+			return x, i + 1
+		}
+	}
+	return 0, len(data)
+}
+
+func fmtStrings(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = s
+		if s == "" {
+			quoted[i] = `""`
+		}
+	}
+	return "[" + strings.Join(quoted, " ") + "]"
+}
@@ -0,0 +1,12 @@
+// Package testname 은 Monkey 테스트 파일과 테스트 함수의 이름 규칙을 정한다.
+//
+// 테스트를 실행하는 testrunner 와 테스트 파일을 다르게 다루는 lint, lsp 가 함께 사용하므로
+// 평가기에 의존하지 않도록 다른 패키지를 import 하지 않는다.
+package testname
+
+const (
+	// FileSuffix 는 테스트 파일 이름의 접미사
+	FileSuffix = "_test.mk"
+	// FuncPrefix 는 테스트 함수 이름의 접두사
+	FuncPrefix = "test_"
+)
@@ -0,0 +1 @@
+let test_skipped = fn() { assert(false) };
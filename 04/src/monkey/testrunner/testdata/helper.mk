@@ -0,0 +1 @@
+let helper = fn() { 1 };
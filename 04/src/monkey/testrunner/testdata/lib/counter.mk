@@ -0,0 +1,3 @@
+let ch = channel(1);
+
+let add = fn(a, b) { a + b };
@@ -0,0 +1,27 @@
+let counter = import "lib/counter.mk";
+let seen = channel(1);
+
+let test_add = fn() {
+  assert_eq(counter["add"](1, 2), 3);
+};
+
+let test_isolated = fn() {
+  assert_eq(select([[seen, 1]], true)[0], 0, "environment shared between tests");
+  assert_eq(select([[counter["ch"], 1]], true)[0], 0, "module shared between tests");
+};
+
+let test_isolated_again = fn() {
+  assert_eq(select([[seen, 1]], true)[0], 0, "environment shared between tests");
+  assert_eq(select([[counter["ch"], 1]], true)[0], 0, "module shared between tests");
+};
+
+let test_fail = fn() {
+  assert_eq([1, 2], [1, 3]);
+};
+
+let test_error = fn() {
+  1 + true;
+};
+
+let helper = fn() { 1 };
+let test_value = 5;
@@ -0,0 +1,4 @@
+let test_upper = fn() {
+  assert_eq(upper("monkey"), "MONKEY");
+  assert_error(fn() { upper(1) }, "STRING");
+};
@@ -0,0 +1 @@
+let test_fixture = fn() { assert(false) };
@@ -0,0 +1,171 @@
+// Package testrunner 는 Monkey 로 작성한 테스트를 찾아서 실행한다.
+//
+// 테스트 파일은 이름이 _test.mk 로 끝나는 파일이고, 테스트 파일의 최상위에서 let 으로 정의한 함수 중
+// 이름이 test_ 로 시작하는 함수가 테스트이다. 테스트 함수는 assert, assert_eq, assert_error 같은
+// 단언 내장함수로 결과를 확인하며, 인자 없이 호출해서 에러 없이 끝나면 통과한다.
+//
+//	let test_add = fn() {
+//	  assert_eq(1 + 2, 3);
+//	};
+//
+// 테스트들이 서로 영향을 주지 않도록 테스트마다 새로운 환경과 모듈 캐시에서 파일 전체를 평가한 뒤 테스트 함수를 호출한다.
+package testrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"monkey/ast"
+	"monkey/evaluator"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"monkey/testname"
+	"monkey/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Find 는 paths 에서 테스트 파일들을 찾는다. 디렉터리는 하위 디렉터리까지 찾되 이름이 . 으로 시작하는 디렉터리와
+// 테스트의 fixture 를 두는 testdata 디렉터리는 건너뛰고, 파일은 이름과 상관없이 그대로 포함한다.
+// 파일들은 paths 의 순서대로, 디렉터리 안에서는 경로 순서대로 반환한다.
+func Find(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if name != path && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(name, testname.FileSuffix) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Test 는 테스트 파일에서 찾은 테스트 함수이다.
+type Test struct {
+	Name string
+	Pos  token.Position // 테스트 함수를 정의한 let 문의 위치
+}
+
+// Tests 는 program 의 최상위에서 let 으로 정의한 테스트 함수들을 소스 코드에 나온 순서대로 반환한다.
+// 같은 이름을 여러 번 정의했다면 처음 정의한 위치로 한 번만 반환한다.
+func Tests(program *ast.Program) []Test {
+	var tests []Test
+	seen := map[string]bool{}
+	for _, stmt := range program.Statements {
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok || !strings.HasPrefix(let.Name.Value, testname.FuncPrefix) || seen[let.Name.Value] {
+			continue
+		}
+		if _, ok := let.Value.(*ast.FunctionLiteral); !ok {
+			continue
+		}
+		seen[let.Name.Value] = true
+		tests = append(tests, Test{Name: let.Name.Value, Pos: let.Pos()})
+	}
+	return tests
+}
+
+// Result 는 테스트 하나를 실행한 결과이다.
+type Result struct {
+	Test
+	File string
+	// Err 는 테스트를 실패하게 한 에러. nil 이면 테스트는 통과한 것이다
+	Err     *object.Error
+	Elapsed time.Duration
+}
+
+// Passed 는 테스트가 통과했는지 반환한다.
+func (r *Result) Passed() bool { return r.Err == nil }
+
+// AssertionFailed 는 테스트가 단언의 실패로 끝났는지 반환한다. false 이면서 Passed 도 false 라면 런타임 에러로 끝난 것이다.
+func (r *Result) AssertionFailed() bool {
+	return r.Err != nil && errors.Is(r.Err, evaluator.ErrAssertion)
+}
+
+// Runner 는 테스트 파일들을 실행한다.
+type Runner struct {
+	// Options 는 테스트마다 적용할 실행 제한이다. Filename 은 테스트 파일마다 설정하고,
+	// Modules 는 검색 경로만 사용하여 테스트마다 새로운 ModuleLoader 를 만든다.
+	Options evaluator.Options
+	// Run 이 nil 이 아니면 이름이 Run 과 일치하는 테스트만 실행한다
+	Run *regexp.Regexp
+}
+
+// RunFile 은 filename 의 테스트들을 차례로 실행하고 결과를 반환한다.
+// 파일을 읽거나 파싱할 수 없으면 에러를 반환한다.
+func (r *Runner) RunFile(ctx context.Context, filename string) ([]Result, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, fmt.Errorf("parser errors: %s", strings.Join(p.Errors(), "; "))
+	}
+
+	var results []Result
+	for _, test := range Tests(program) {
+		if r.Run != nil && !r.Run.MatchString(test.Name) {
+			continue
+		}
+		start := time.Now()
+		err := r.run(ctx, filename, program, test)
+		results = append(results, Result{Test: test, File: filename, Err: err, Elapsed: time.Since(start)})
+	}
+	return results, nil
+}
+
+// run 은 새로운 환경에서 program 을 평가한 뒤 테스트 함수를 호출한다
+func (r *Runner) run(ctx context.Context, filename string, program *ast.Program, test Test) *object.Error {
+	opts := r.Options
+	opts.Filename = filename
+	paths := evaluator.DefaultModuleLoader.Paths
+	if opts.Modules != nil {
+		paths = opts.Modules.Paths
+	}
+	opts.Modules = evaluator.NewModuleLoader(paths...)
+
+	env := object.NewEnvironment()
+	if errObj, ok := evaluator.EvalContext(ctx, program, env, opts).(*object.Error); ok {
+		return errObj
+	}
+
+	// test_add() 를 평가하는 것과 같다. 호출의 위치는 테스트 함수를 정의한 let 문의 위치로 한다
+	tok := token.Token{Type: token.IDENT, Literal: test.Name, Pos: test.Pos}
+	call := &ast.CallExpression{
+		Token:    token.Token{Type: token.LPAREN, Literal: "(", Pos: test.Pos},
+		Function: &ast.Identifier{Token: tok, Value: test.Name},
+	}
+	if errObj, ok := evaluator.EvalContext(ctx, call, env, opts).(*object.Error); ok {
+		return errObj
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package testrunner
+
+import (
+	"context"
+	"monkey/lexer"
+	"monkey/parser"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	files, err := Find([]string{"testdata", filepath.Join("testdata", "helper.mk")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		filepath.Join("testdata", "broken_test.mk"),
+		filepath.Join("testdata", "math_test.mk"),
+		filepath.Join("testdata", "sub", "strings_test.mk"),
+		filepath.Join("testdata", "helper.mk"),
+	}
+	if strings.Join(files, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong files.\nexpected=%q\ngot=     %q", expected, files)
+	}
+
+	if _, err := Find([]string{"missing"}); err == nil {
+		t.Errorf("Find of missing path returned no error")
+	}
+}
+
+func TestTests(t *testing.T) {
+	input := `let test_a = fn() { 1 };
+let helper = fn() { 2 };
+let test_value = 3;
+let test_b = fn(x) { x };
+let test_a = fn() { 4 };
+test_a();`
+
+	program := parser.New(lexer.New(input)).ParseProgram()
+	tests := Tests(program)
+
+	expected := []string{"test_a 1:1", "test_b 4:1"}
+	var got []string
+	for _, test := range tests {
+		got = append(got, test.Name+" "+test.Pos.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong tests.\nexpected=%q\ngot=     %q", expected, got)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	r := &Runner{}
+	results, err := r.RunFile(context.Background(), filepath.Join("testdata", "math_test.mk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		name      string
+		passed    bool
+		assertion bool
+		message   string
+	}{
+		{"test_add", true, false, ""},
+		{"test_isolated", true, false, ""},
+		{"test_isolated_again", true, false, ""},
+		{"test_fail", false, true, "assert_eq failed\n  actual:   [1, 2]\n  expected: [1, 3]\n  at [1]: actual 2, expected 3"},
+		{"test_error", false, false, "type mismatch: INTEGER + BOOLEAN"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("wrong number of results. got=%+v", results)
+	}
+	for i, tt := range expected {
+		res := results[i]
+		if res.Name != tt.name || res.Passed() != tt.passed || res.AssertionFailed() != tt.assertion {
+			t.Errorf("results[%d] wrong. expected=%s passed=%t assertion=%t, got=%s passed=%t assertion=%t (%v)",
+				i, tt.name, tt.passed, tt.assertion, res.Name, res.Passed(), res.AssertionFailed(), res.Err)
+			continue
+		}
+		if !tt.passed && res.Err.Message != tt.message {
+			t.Errorf("results[%d] wrong message.\nexpected=%q\ngot=     %q", i, tt.message, res.Err.Message)
+		}
+	}
+}
+
+func TestRunFileMatch(t *testing.T) {
+	r := &Runner{Run: regexp.MustCompile("isolated")}
+	results, err := r.RunFile(context.Background(), filepath.Join("testdata", "math_test.mk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Name != "test_isolated" || results[1].Name != "test_isolated_again" {
+		t.Errorf("wrong results. got=%+v", results)
+	}
+
+	if _, err := r.RunFile(context.Background(), filepath.Join("testdata", "broken_test.mk")); err == nil {
+		t.Errorf("RunFile of invalid source returned no error")
+	}
+}
@@ -1,5 +1,10 @@
 package token
 
+import (
+	"fmt"
+	"sort"
+)
+
 type TokenType string
 
 const (
@@ -45,11 +50,23 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	IMPORT   = "IMPORT" // 다른 파일의 모듈을 불러오기 위함
 )
 
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type    TokenType `json:"type"`
+	Literal string    `json:"literal"`
+	Pos     Position  `json:"pos"` // 토큰이 시작하는 위치
+}
+
+// Position 소스 코드에서의 위치. Line 과 Column 은 1 부터 시작하며, Column 은 바이트 단위이다.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 var keywords = map[string]TokenType{
@@ -60,6 +77,7 @@ var keywords = map[string]TokenType{
 	"if":     IF,
 	"else":   ELSE,
 	"return": RETURN,
+	"import": IMPORT,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -68,3 +86,13 @@ func LookupIdent(ident string) TokenType {
 	}
 	return IDENT
 }
+
+// Keywords 는 모든 키워드를 정렬하여 반환한다
+func Keywords() []string {
+	words := make([]string, 0, len(keywords))
+	for word := range keywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}